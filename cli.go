@@ -9,6 +9,7 @@ import (
 	"os"
 
 	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
 	"github.com/hashicorp/tfci/internal/writer"
 	"github.com/hashicorp/tfci/version"
 
@@ -20,6 +21,8 @@ var (
 	hostnameFlag     = flag.String("hostname", "", "The hostname of a Terraform Enterprise installation, if using Terraform Enterprise. Defaults to HCP Terraform (app.terraform.io)")
 	tokenFlag        = flag.String("token", "", "The token used to authenticate with HCP Terraform. Defaults to reading `TF_API_TOKEN` environment variable")
 	organizationFlag = flag.String("organization", "", "HCP Terraform Organization Name")
+	outputFileFlag   = flag.String("output-file", "", "Writes command outputs to a file, in addition to any CI platform output. Defaults to reading `TFCI_OUTPUT_FILE` environment variable")
+	outputFormatFlag = flag.String("output-format", "", "Format of the output file: json, dotenv, shell or tfvars. Defaults to reading `TFCI_OUTPUT_FORMAT` environment variable, otherwise json")
 )
 
 func newCliRunner() (*cli.CLI, error) {
@@ -44,8 +47,21 @@ func newCliRunner() (*cli.CLI, error) {
 	}
 	log.Printf("[DEBUG] Subcommand arg count: %d for organization: %s", len(newArgs), orgEnv)
 
-	tfe, err := cloud.NewTfeClient(*hostnameFlag, *tokenFlag, string(env.PlatformType))
+	if *outputFileFlag == "" {
+		*outputFileFlag = os.Getenv("TFCI_OUTPUT_FILE")
+	}
+	if *outputFormatFlag == "" {
+		*outputFormatFlag = os.Getenv("TFCI_OUTPUT_FORMAT")
+	}
+	outputFormat, err := environment.ParseFileOutputFormat(*outputFormatFlag)
 	if err != nil {
+		return nil, err
+	}
+
+	tfe, err := cloud.NewTfeClient(*hostnameFlag, *tokenFlag, string(env.PlatformType))
+	if err != nil && isOfflineCommand(newArgs) {
+		log.Printf("[DEBUG] Continuing without HCP Terraform client for local files, error: %s", err)
+	} else if err != nil {
 		log.Printf("[ERROR] Could not initialize HCP Terraform client, error: %#v", err)
 		return nil, err
 	}
@@ -58,6 +74,7 @@ func newCliRunner() (*cli.CLI, error) {
 		env,
 		cmd.WithOrg(*organizationFlag),
 		cmd.WithWriter(writer),
+		cmd.WithOutputFile(*outputFileFlag, outputFormat),
 	)
 
 	cliRunner.Commands = map[string]cli.CommandFactory{
@@ -70,6 +87,15 @@ func newCliRunner() (*cli.CLI, error) {
 		"run apply": func() (cli.Command, error) {
 			return &cmd.ApplyRunCommand{Meta: meta}, nil
 		},
+		"run wait": func() (cli.Command, error) {
+			return &cmd.WaitRunCommand{Meta: meta}, nil
+		},
+		"run watch": func() (cli.Command, error) {
+			return &cmd.WatchRunCommand{Meta: meta}, nil
+		},
+		"run list": func() (cli.Command, error) {
+			return &cmd.ListRunsCommand{Meta: meta}, nil
+		},
 		"run show": func() (cli.Command, error) {
 			return &cmd.ShowRunCommand{Meta: meta}, nil
 		},
@@ -82,6 +108,9 @@ func newCliRunner() (*cli.CLI, error) {
 		"plan output": func() (cli.Command, error) {
 			return &cmd.OutputPlanCommand{Meta: meta}, nil
 		},
+		"plan check": func() (cli.Command, error) {
+			return &cmd.CheckPlanCommand{Meta: meta}, nil
+		},
 		"workspace output list": func() (cli.Command, error) {
 			return &cmd.WorkspaceOutputCommand{Meta: meta}, nil
 		},
@@ -95,3 +124,11 @@ func newCliRunner() (*cli.CLI, error) {
 
 	return cliRunner, nil
 }
+
+// reports whether the command only reads local files, such as plan check of a plan file
+func isOfflineCommand(args []string) bool {
+	if len(args) < 2 || args[0] != "plan" || args[1] != "check" {
+		return false
+	}
+	return cmd.IsOfflinePlanCheck(args[2:])
+}
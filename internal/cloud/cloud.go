@@ -38,6 +38,11 @@ func (c *Cloud) UseJson(json bool) {
 	c.writer.UseJson(json)
 }
 
+// UseLogOptions configures how plan and apply logs are printed
+func (c *Cloud) UseLogOptions(opts LogOptions) {
+	c.logOptions = opts
+}
+
 // RunLinkByID constructs a run link URL using only the run ID.
 // This is useful when we don't have the full Run object (e.g., from policy operations).
 func (c *Cloud) RunLinkByID(organization, runID string) string {
@@ -49,6 +54,8 @@ func (c *Cloud) RunLinkByID(organization, runID string) string {
 type cloudMeta struct {
 	tfe    *tfe.Client
 	writer Writer
+	// zero value prints logs as received
+	logOptions LogOptions
 }
 
 func NewCloud(c *tfe.Client, w Writer) *Cloud {
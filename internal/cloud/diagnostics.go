@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// number of trailing log lines kept for excerpts
+const runLogExcerptLines = 50
+
+// Diagnostic severities emitted by Terraform
+const (
+	DiagnosticError   = "error"
+	DiagnosticWarning = "warning"
+)
+
+// Diagnostic is a Terraform diagnostic decoded from a structured (JSON UI) log line
+// https://developer.hashicorp.com/terraform/internals/machine-readable-ui#diagnostic
+type Diagnostic struct {
+	Severity string           `json:"severity"`
+	Summary  string           `json:"summary"`
+	Detail   string           `json:"detail"`
+	Address  string           `json:"address,omitempty"`
+	Range    *DiagnosticRange `json:"range,omitempty"`
+}
+
+// DiagnosticRange is the source location a diagnostic refers to
+type DiagnosticRange struct {
+	Filename string        `json:"filename"`
+	Start    DiagnosticPos `json:"start"`
+	End      DiagnosticPos `json:"end"`
+}
+
+type DiagnosticPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// ChangeSummary is the resource change count reported once a plan or apply completed
+// https://developer.hashicorp.com/terraform/internals/machine-readable-ui#change-summary
+type ChangeSummary struct {
+	Add       int    `json:"add"`
+	Change    int    `json:"change"`
+	Import    int    `json:"import"`
+	Remove    int    `json:"remove"`
+	Operation string `json:"operation"`
+	// human-readable summary, eg. Plan: 1 to add, 0 to change, 0 to destroy.
+	Message string `json:"-"`
+}
+
+// RunLog contains details parsed from plan or apply logs while they are written
+type RunLog struct {
+	Diagnostics []*Diagnostic
+	// nil when the log does not contain a change summary, eg. for errored plans
+	ChangeSummary *ChangeSummary
+	// trailing human-readable lines of the log
+	excerpt []string
+}
+
+// ErrorCount returns the number of error diagnostics
+func (l *RunLog) ErrorCount() int {
+	count := 0
+	for _, d := range l.Diagnostics {
+		if d.Severity == DiagnosticError {
+			count++
+		}
+	}
+	return count
+}
+
+// ErrorSummary returns one line per error diagnostic, including the source location when known
+func (l *RunLog) ErrorSummary() string {
+	var lines []string
+	for _, d := range l.Diagnostics {
+		if d.Severity != DiagnosticError {
+			continue
+		}
+		line := fmt.Sprintf("Error: %s", d.Summary)
+		if d.Range != nil && d.Range.Filename != "" {
+			line += fmt.Sprintf(" (%s line %d)", d.Range.Filename, d.Range.Start.Line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// common fields of each structured log message, with the payload of the message types used
+type jsonLogMessage struct {
+	Level      string                `json:"@level"`
+	Message    string                `json:"@message"`
+	Module     string                `json:"@module"`
+	Type       string                `json:"type"`
+	Diagnostic *Diagnostic           `json:"diagnostic,omitempty"`
+	Change     *jsonResourceChange   `json:"change,omitempty"`
+	Changes    *ChangeSummary        `json:"changes,omitempty"`
+	Outputs    map[string]jsonOutput `json:"outputs,omitempty"`
+}
+
+// decodes a structured log line, returns nil for unstructured (human-readable) lines
+func parseJSONLogLine(line []byte) *jsonLogMessage {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil
+	}
+	msg := &jsonLogMessage{}
+	if err := json.Unmarshal(trimmed, msg); err != nil {
+		return nil
+	}
+	if msg.Type == "" {
+		return nil
+	}
+	return msg
+}
+
+// Excerpt returns the trailing lines of the log, structured lines are reduced to their message
+func (l *RunLog) Excerpt() string {
+	return strings.Join(l.excerpt, "\n")
+}
+
+// collects details from a log line into the run log, returns the decoded message of structured lines
+func (l *RunLog) parseLine(line []byte) *jsonLogMessage {
+	msg := parseJSONLogLine(line)
+	if msg == nil {
+		l.addExcerpt(strings.TrimRight(string(line), "\r\n"))
+		return nil
+	}
+	l.addExcerpt(msg.Message)
+	switch {
+	case msg.Type == "diagnostic" && msg.Diagnostic != nil:
+		l.Diagnostics = append(l.Diagnostics, msg.Diagnostic)
+	case msg.Type == "change_summary" && msg.Changes != nil:
+		l.ChangeSummary = msg.Changes
+		l.ChangeSummary.Message = msg.Message
+	}
+	return msg
+}
+
+func (l *RunLog) addExcerpt(line string) {
+	l.excerpt = append(l.excerpt, line)
+	if len(l.excerpt) > runLogExcerptLines {
+		l.excerpt = l.excerpt[len(l.excerpt)-runLogExcerptLines:]
+	}
+}
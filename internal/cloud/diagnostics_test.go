@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputRunLogLines_Diagnostics(t *testing.T) {
+	logs := strings.Join([]string{
+		`Terraform v1.6.0`,
+		`{"@level":"info","@message":"Terraform 1.6.0","@module":"terraform.ui","type":"version","terraform":"1.6.0","ui":"1.2"}`,
+		`{"@level":"error","@message":"Error: Unsupported argument","@module":"terraform.ui","type":"diagnostic","diagnostic":{"severity":"error","summary":"Unsupported argument","detail":"An argument named \"foo\" is not expected here.","range":{"filename":"main.tf","start":{"line":3,"column":3,"byte":40},"end":{"line":3,"column":6,"byte":43}}}}`,
+		`{"@level":"warn","@message":"Warning: Deprecated attribute","@module":"terraform.ui","type":"diagnostic","diagnostic":{"severity":"warning","summary":"Deprecated attribute","detail":""}}`,
+		`{not json`,
+	}, "\n")
+
+	runLog, err := outputRunLogLines(strings.NewReader(logs), &defaultWriter{}, LogOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(runLog.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, but received: %d", len(runLog.Diagnostics))
+	}
+	if runLog.ErrorCount() != 1 {
+		t.Errorf("expected 1 error diagnostic, but received: %d", runLog.ErrorCount())
+	}
+
+	diag := runLog.Diagnostics[0]
+	if diag.Severity != DiagnosticError || diag.Summary != "Unsupported argument" {
+		t.Errorf("unexpected diagnostic: %+v", diag)
+	}
+	if diag.Range == nil || diag.Range.Filename != "main.tf" || diag.Range.Start.Line != 3 || diag.Range.End.Column != 6 {
+		t.Errorf("unexpected diagnostic range: %+v", diag.Range)
+	}
+
+	expectedExcerpt := "Terraform v1.6.0\nTerraform 1.6.0\nError: Unsupported argument\nWarning: Deprecated attribute\n{not json"
+	if runLog.Excerpt() != expectedExcerpt {
+		t.Errorf("expected excerpt %q, but received: %q", expectedExcerpt, runLog.Excerpt())
+	}
+
+	if runLog.Diagnostics[1].Range != nil {
+		t.Errorf("expected diagnostic without range, but received: %+v", runLog.Diagnostics[1].Range)
+	}
+
+	if summary := runLog.ErrorSummary(); summary != "Error: Unsupported argument (main.tf line 3)" {
+		t.Errorf("unexpected error summary: %q", summary)
+	}
+	if runLog.ChangeSummary != nil {
+		t.Errorf("expected no change summary, but received: %+v", runLog.ChangeSummary)
+	}
+}
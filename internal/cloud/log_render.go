@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LogView controls how structured (JSON UI) plan and apply log lines are printed,
+// unstructured lines are always printed as they are
+type LogView string
+
+const (
+	// prints log lines as received
+	LogViewRaw LogView = "raw"
+	// prints the human-readable message of each structured line, similar to the Terraform CLI
+	LogViewCompact LogView = "compact"
+	// prints resource changes, drift, change summaries, outputs and errors
+	LogViewChanges LogView = "changes"
+	// prints errors only
+	LogViewErrors LogView = "errors"
+)
+
+var logViews = []LogView{LogViewRaw, LogViewCompact, LogViewChanges, LogViewErrors}
+
+// ParseLogView validates the log view, defaults to compact
+func ParseLogView(v string) (LogView, error) {
+	if v == "" {
+		return LogViewCompact, nil
+	}
+	for _, view := range logViews {
+		if LogView(v) == view {
+			return view, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported log view: %q, expected one of: %v", v, logViews)
+}
+
+// LogOptions configures how plan and apply logs are printed
+type LogOptions struct {
+	View LogView
+	// omits refresh progress of resources and data sources
+	HideRefresh bool
+}
+
+// prefix of each planned change action, following the Terraform CLI
+var changeActionSymbols = map[string]string{
+	"create":  "+",
+	"read":    "<=",
+	"update":  "~",
+	"replace": "-/+",
+	"delete":  "-",
+	"move":    "->",
+	"import":  "<-",
+	"remove":  "x",
+}
+
+// message types describing refresh progress
+var refreshMessageTypes = map[string]bool{
+	"refresh_start":    true,
+	"refresh_complete": true,
+}
+
+// resource change of planned_change and resource_drift messages
+type jsonResourceChange struct {
+	Resource struct {
+		Addr string `json:"addr"`
+	} `json:"resource"`
+	PreviousResource *struct {
+		Addr string `json:"addr"`
+	} `json:"previous_resource,omitempty"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// output of outputs messages, action is set for plans and value for applies
+type jsonOutput struct {
+	Sensitive bool   `json:"sensitive"`
+	Action    string `json:"action,omitempty"`
+}
+
+// returns the lines printed for a log line, structured lines are decoded to msg
+func (o LogOptions) render(line []byte, msg *jsonLogMessage) []string {
+	if msg == nil {
+		text := strings.TrimRight(string(line), "\r\n")
+		if o.HideRefresh && strings.Contains(text, ": Refreshing state...") {
+			return nil
+		}
+		return []string{text}
+	}
+	if o.HideRefresh && refreshMessageTypes[msg.Type] {
+		return nil
+	}
+
+	switch o.View {
+	case LogViewRaw, "":
+		return []string{strings.TrimRight(string(line), "\r\n")}
+	case LogViewErrors:
+		if msg.Type == "diagnostic" && msg.Diagnostic != nil && msg.Diagnostic.Severity == DiagnosticError {
+			return renderDiagnostic(msg.Diagnostic)
+		}
+		if msg.Type == "apply_errored" {
+			return []string{msg.Message}
+		}
+		return nil
+	case LogViewChanges:
+		switch msg.Type {
+		case "planned_change", "resource_drift", "change_summary", "outputs", "apply_complete", "apply_errored":
+			return renderCompact(msg)
+		case "diagnostic":
+			if msg.Diagnostic != nil && msg.Diagnostic.Severity == DiagnosticError {
+				return renderDiagnostic(msg.Diagnostic)
+			}
+		}
+		return nil
+	default:
+		return renderCompact(msg)
+	}
+}
+
+func renderCompact(msg *jsonLogMessage) []string {
+	switch msg.Type {
+	case "planned_change":
+		if msg.Change != nil {
+			return []string{renderResourceChange(msg.Change, "")}
+		}
+	case "resource_drift":
+		if msg.Change != nil {
+			return []string{renderResourceChange(msg.Change, "changed outside of Terraform")}
+		}
+	case "diagnostic":
+		if msg.Diagnostic != nil {
+			return renderDiagnostic(msg.Diagnostic)
+		}
+	case "outputs":
+		return renderOutputs(msg)
+	}
+	return []string{msg.Message}
+}
+
+func renderResourceChange(change *jsonResourceChange, note string) string {
+	symbol, ok := changeActionSymbols[change.Action]
+	if !ok {
+		symbol = "?"
+	}
+	line := fmt.Sprintf("  %s %s", symbol, change.Resource.Addr)
+
+	var notes []string
+	if change.Action == "move" && change.PreviousResource != nil {
+		notes = append(notes, fmt.Sprintf("moved from %s", change.PreviousResource.Addr))
+	}
+	if change.Reason != "" {
+		notes = append(notes, strings.ReplaceAll(change.Reason, "_", " "))
+	}
+	if note != "" {
+		notes = append(notes, note)
+	}
+	if len(notes) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(notes, ", "))
+	}
+	return line
+}
+
+func renderDiagnostic(diag *Diagnostic) []string {
+	title := "Warning"
+	if diag.Severity == DiagnosticError {
+		title = "Error"
+	}
+	lines := []string{fmt.Sprintf("%s: %s", title, diag.Summary)}
+	if diag.Address != "" {
+		lines = append(lines, fmt.Sprintf("  with %s", diag.Address))
+	}
+	if diag.Range != nil && diag.Range.Filename != "" {
+		lines = append(lines, fmt.Sprintf("  on %s line %d", diag.Range.Filename, diag.Range.Start.Line))
+	}
+	if diag.Detail != "" {
+		for _, l := range strings.Split(diag.Detail, "\n") {
+			lines = append(lines, strings.TrimRight("  "+l, " "))
+		}
+	}
+	return lines
+}
+
+func renderOutputs(msg *jsonLogMessage) []string {
+	lines := []string{msg.Message}
+	names := make([]string, 0, len(msg.Outputs))
+	for name := range msg.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		output := msg.Outputs[name]
+		line := fmt.Sprintf("  %s", name)
+		if symbol, ok := changeActionSymbols[output.Action]; ok {
+			line = fmt.Sprintf("  %s %s", symbol, name)
+		}
+		if output.Sensitive {
+			line += " (sensitive)"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"strings"
+	"testing"
+)
+
+// recorded from a plan with a drifted, a replaced and a refreshed resource
+var testPlanLog = strings.Join([]string{
+	`Terraform v1.6.0`,
+	`{"@level":"info","@message":"Terraform 1.6.0","@module":"terraform.ui","type":"version","terraform":"1.6.0","ui":"1.2"}`,
+	`{"@level":"info","@message":"random_pet.name: Refreshing state... [id=sunny-dog]","@module":"terraform.ui","type":"refresh_start","hook":{"resource":{"addr":"random_pet.name"},"id_key":"id","id_value":"sunny-dog"}}`,
+	`{"@level":"info","@message":"random_pet.name: Refresh complete [id=sunny-dog]","@module":"terraform.ui","type":"refresh_complete","hook":{"resource":{"addr":"random_pet.name"},"id_key":"id","id_value":"sunny-dog"}}`,
+	`{"@level":"info","@message":"aws_s3_bucket.logs: Drift detected (update)","@module":"terraform.ui","type":"resource_drift","change":{"resource":{"addr":"aws_s3_bucket.logs"},"action":"update"}}`,
+	`{"@level":"info","@message":"aws_instance.web: Plan to replace","@module":"terraform.ui","type":"planned_change","change":{"resource":{"addr":"aws_instance.web"},"action":"replace","reason":"cannot_update"}}`,
+	`{"@level":"info","@message":"module.db.aws_db_instance.this: Plan to move","@module":"terraform.ui","type":"planned_change","change":{"resource":{"addr":"module.db.aws_db_instance.this"},"previous_resource":{"addr":"aws_db_instance.this"},"action":"move"}}`,
+	`{"@level":"warn","@message":"Warning: Deprecated attribute","@module":"terraform.ui","type":"diagnostic","diagnostic":{"severity":"warning","summary":"Deprecated attribute","detail":""}}`,
+	`{"@level":"info","@message":"Plan: 1 to add, 0 to change, 1 to destroy.","@module":"terraform.ui","type":"change_summary","changes":{"add":1,"change":0,"import":0,"remove":1,"operation":"plan"}}`,
+	`{"@level":"info","@message":"Outputs: 2","@module":"terraform.ui","type":"outputs","outputs":{"password":{"sensitive":true,"action":"create"},"ip":{"sensitive":false,"action":"update"}}}`,
+}, "\n")
+
+func TestOutputRunLogLines_Render(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     LogOptions
+		expected []string
+	}{
+		{
+			name: "compact",
+			opts: LogOptions{View: LogViewCompact},
+			expected: []string{
+				"Terraform v1.6.0",
+				"Terraform 1.6.0",
+				"random_pet.name: Refreshing state... [id=sunny-dog]",
+				"random_pet.name: Refresh complete [id=sunny-dog]",
+				"  ~ aws_s3_bucket.logs (changed outside of Terraform)",
+				"  -/+ aws_instance.web (cannot update)",
+				"  -> module.db.aws_db_instance.this (moved from aws_db_instance.this)",
+				"Warning: Deprecated attribute",
+				"Plan: 1 to add, 0 to change, 1 to destroy.",
+				"Outputs: 2",
+				"  ~ ip",
+				"  + password (sensitive)",
+			},
+		},
+		{
+			name: "compact-hide-refresh",
+			opts: LogOptions{View: LogViewCompact, HideRefresh: true},
+			expected: []string{
+				"Terraform v1.6.0",
+				"Terraform 1.6.0",
+				"  ~ aws_s3_bucket.logs (changed outside of Terraform)",
+				"  -/+ aws_instance.web (cannot update)",
+				"  -> module.db.aws_db_instance.this (moved from aws_db_instance.this)",
+				"Warning: Deprecated attribute",
+				"Plan: 1 to add, 0 to change, 1 to destroy.",
+				"Outputs: 2",
+				"  ~ ip",
+				"  + password (sensitive)",
+			},
+		},
+		{
+			name: "changes",
+			opts: LogOptions{View: LogViewChanges},
+			expected: []string{
+				"Terraform v1.6.0",
+				"  ~ aws_s3_bucket.logs (changed outside of Terraform)",
+				"  -/+ aws_instance.web (cannot update)",
+				"  -> module.db.aws_db_instance.this (moved from aws_db_instance.this)",
+				"Plan: 1 to add, 0 to change, 1 to destroy.",
+				"Outputs: 2",
+				"  ~ ip",
+				"  + password (sensitive)",
+			},
+		},
+		{
+			name:     "errors",
+			opts:     LogOptions{View: LogViewErrors},
+			expected: []string{"Terraform v1.6.0"},
+		},
+		{
+			name:     "raw",
+			opts:     LogOptions{View: LogViewRaw},
+			expected: strings.Split(testPlanLog, "\n"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			writer := &recordingWriter{}
+			runLog, err := outputRunLogLines(strings.NewReader(testPlanLog), writer, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if strings.Join(writer.messages, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected:\n%s\nbut received:\n%s", strings.Join(tc.expected, "\n"), strings.Join(writer.messages, "\n"))
+			}
+
+			// parsed details do not depend on the view
+			if runLog.ChangeSummary == nil || runLog.ChangeSummary.Add != 1 || runLog.ChangeSummary.Remove != 1 {
+				t.Fatalf("unexpected change summary: %+v", runLog.ChangeSummary)
+			}
+			if runLog.ChangeSummary.Message != "Plan: 1 to add, 0 to change, 1 to destroy." {
+				t.Errorf("unexpected change summary message: %q", runLog.ChangeSummary.Message)
+			}
+		})
+	}
+}
+
+func TestRenderDiagnostic(t *testing.T) {
+	diag := &Diagnostic{
+		Severity: DiagnosticError,
+		Summary:  "Unsupported argument",
+		Detail:   "An argument named \"foo\" is not expected here.\n\nDid you mean \"for\"?",
+		Address:  "aws_instance.web",
+		Range:    &DiagnosticRange{Filename: "main.tf", Start: DiagnosticPos{Line: 3}},
+	}
+
+	expected := []string{
+		"Error: Unsupported argument",
+		"  with aws_instance.web",
+		"  on main.tf line 3",
+		"  An argument named \"foo\" is not expected here.",
+		"",
+		"  Did you mean \"for\"?",
+	}
+	if actual := renderDiagnostic(diag); strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %q, but received: %q", expected, actual)
+	}
+}
+
+func TestParseLogView(t *testing.T) {
+	if view, err := ParseLogView(""); err != nil || view != LogViewCompact {
+		t.Errorf("expected default view compact, but received: %q, %v", view, err)
+	}
+	if view, err := ParseLogView("changes"); err != nil || view != LogViewChanges {
+		t.Errorf("expected view changes, but received: %q, %v", view, err)
+	}
+	if _, err := ParseLogView("verbose"); err == nil {
+		t.Error("expected error for unsupported view")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// LogStream tails the log of a plan or apply in the background, lines are written as they arrive.
+// Streams are not bound to LogTimeout and end once the plan or apply has completed.
+type LogStream struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	runLog *RunLog
+	err    error
+}
+
+// Wait blocks until the log has been read completely or the stream was canceled,
+// the returned log is nil when the log could not be opened
+func (s *LogStream) Wait() (*RunLog, error) {
+	if s == nil {
+		return nil, nil
+	}
+	<-s.done
+	return s.runLog, s.err
+}
+
+// Cancel stops reading the log, lines read so far are kept
+func (s *LogStream) Cancel() {
+	if s == nil {
+		return
+	}
+	s.cancel()
+}
+
+// PlanStarted reports whether the plan of run is in progress or completed, and its log can be read
+func PlanStarted(run *tfe.Run) bool {
+	if run == nil || run.Plan == nil {
+		return false
+	}
+	switch run.Plan.Status {
+	case tfe.PlanRunning, tfe.PlanFinished, tfe.PlanErrored, tfe.PlanCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// ApplyStarted reports whether the apply of run is in progress or completed, and its log can be read
+func ApplyStarted(run *tfe.Run) bool {
+	if run == nil || run.Apply == nil {
+		return false
+	}
+	switch run.Apply.Status {
+	case tfe.ApplyRunning, tfe.ApplyFinished, tfe.ApplyErrored, tfe.ApplyCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+func (service *runService) StreamPlanLogs(ctx context.Context, planID string) *LogStream {
+	return service.streamLogs(ctx, "Plan Log", func(ctx context.Context) (io.Reader, error) {
+		return service.tfe.Plans.Logs(ctx, planID)
+	})
+}
+
+func (service *runService) StreamApplyLogs(ctx context.Context, applyID string) *LogStream {
+	return service.streamLogs(ctx, "Apply Log", func(ctx context.Context) (io.Reader, error) {
+		return service.tfe.Applies.Logs(ctx, applyID)
+	})
+}
+
+func (service *runService) streamLogs(ctx context.Context, title string, open func(context.Context) (io.Reader, error)) *LogStream {
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream := &LogStream{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(stream.done)
+		defer cancel()
+
+		logReader, err := open(streamCtx)
+		if err != nil {
+			stream.err = err
+			return
+		}
+
+		service.writer.Output(fmt.Sprintf("-------------- %s --------------", title))
+		stream.runLog, err = outputRunLogLines(logReader, service.writer, service.logOptions)
+		// canceled streams only stop tailing, lines read so far are valid
+		if err != nil && !errors.Is(err, context.Canceled) {
+			stream.err = err
+		}
+	}()
+
+	return stream
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+// records messages written from the stream goroutine
+type recordingWriter struct {
+	defaultWriter
+	mu       sync.Mutex
+	messages []string
+	errors   []string
+}
+
+func (w *recordingWriter) Output(msg string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, msg)
+}
+
+func (w *recordingWriter) Error(msg string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.errors = append(w.errors, msg)
+}
+
+// blocks reads until the context is canceled, like a log of a plan that is still running
+type pendingLogReader struct {
+	ctx  context.Context
+	sent bool
+}
+
+func (r *pendingLogReader) Read(p []byte) (int, error) {
+	if !r.sent {
+		r.sent = true
+		return copy(p, "Terraform v1.6.0\n"), nil
+	}
+	<-r.ctx.Done()
+	return 0, r.ctx.Err()
+}
+
+func TestRunService_StreamPlanLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	plansMock := mocks.NewMockPlans(ctrl)
+	plansMock.EXPECT().Logs(gomock.Any(), "plan-123").Return(strings.NewReader("Terraform v1.6.0\nPlan: 1 to add, 0 to change, 0 to destroy."), nil)
+
+	writer := &recordingWriter{}
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Plans: plansMock}, writer: writer})
+
+	runLog, err := service.StreamPlanLogs(context.Background(), "plan-123").Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if runLog == nil || runLog.Excerpt() != "Terraform v1.6.0\nPlan: 1 to add, 0 to change, 0 to destroy." {
+		t.Errorf("unexpected run log: %+v", runLog)
+	}
+
+	expected := []string{"-------------- Plan Log --------------", "Terraform v1.6.0", "Plan: 1 to add, 0 to change, 0 to destroy."}
+	if strings.Join(writer.messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %q, but received: %q", expected, writer.messages)
+	}
+}
+
+func TestRunService_StreamApplyLogs_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	appliesMock := mocks.NewMockApplies(ctrl)
+	appliesMock.EXPECT().Logs(gomock.Any(), "apply-123").DoAndReturn(func(ctx context.Context, applyID string) (io.Reader, error) {
+		return &pendingLogReader{ctx: ctx}, nil
+	})
+
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Applies: appliesMock}, writer: &recordingWriter{}})
+	stream := service.StreamApplyLogs(context.Background(), "apply-123")
+
+	waited := make(chan struct{})
+	var runLog *RunLog
+	var err error
+	go func() {
+		defer close(waited)
+		runLog, err = stream.Wait()
+	}()
+
+	stream.Cancel()
+	select {
+	case <-waited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected canceled stream to end")
+	}
+
+	if err != nil {
+		t.Errorf("expected canceled stream to keep lines without error, received: %s", err)
+	}
+	if runLog == nil || runLog.Excerpt() != "Terraform v1.6.0" {
+		t.Errorf("unexpected run log: %+v", runLog)
+	}
+}
+
+func TestPlanStarted(t *testing.T) {
+	testCases := []struct {
+		run      *tfe.Run
+		expected bool
+	}{
+		{run: nil, expected: false},
+		{run: &tfe.Run{}, expected: false},
+		{run: &tfe.Run{Plan: &tfe.Plan{Status: tfe.PlanPending}}, expected: false},
+		{run: &tfe.Run{Plan: &tfe.Plan{Status: tfe.PlanQueued}}, expected: false},
+		{run: &tfe.Run{Plan: &tfe.Plan{Status: tfe.PlanUnreachable}}, expected: false},
+		{run: &tfe.Run{Plan: &tfe.Plan{Status: tfe.PlanRunning}}, expected: true},
+		{run: &tfe.Run{Plan: &tfe.Plan{Status: tfe.PlanFinished}}, expected: true},
+		{run: &tfe.Run{Plan: &tfe.Plan{Status: tfe.PlanErrored}}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		if actual := PlanStarted(tc.run); actual != tc.expected {
+			t.Errorf("expected %t for %+v, but received: %t", tc.expected, tc.run, actual)
+		}
+	}
+}
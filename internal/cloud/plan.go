@@ -12,6 +12,13 @@ import (
 
 type PlanService interface {
 	GetPlan(context.Context, string) (*tfe.Plan, error)
+	GetPlanJSON(context.Context, GetPlanJSONOptions) ([]byte, error)
+}
+
+type GetPlanJSONOptions struct {
+	PlanID string
+	// keeps sensitive values in the JSON execution plan, they are redacted by default
+	ShowSensitive bool
 }
 
 type planService struct {
@@ -27,6 +34,19 @@ func (service *planService) GetPlan(ctx context.Context, planID string) (*tfe.Pl
 	return data, nil
 }
 
+// GetPlanJSON downloads the JSON execution plan, the plan must have finished
+func (service *planService) GetPlanJSON(ctx context.Context, options GetPlanJSONOptions) ([]byte, error) {
+	data, err := service.tfe.Plans.ReadJSONOutput(ctx, options.PlanID)
+	if err != nil {
+		log.Printf("[ERROR] error reading JSON execution plan: '%s', with: '%s'", options.PlanID, err.Error())
+		return nil, err
+	}
+	if options.ShowSensitive {
+		return data, nil
+	}
+	return RedactPlanJSON(data)
+}
+
 func NewPlanService(meta *cloudMeta) *planService {
 	return &planService{meta}
 }
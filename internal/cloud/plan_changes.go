@@ -0,0 +1,235 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Resource change actions, resolved from the action list of a JSON execution plan
+const (
+	ChangeCreate  = "create"
+	ChangeUpdate  = "update"
+	ChangeReplace = "replace"
+	ChangeDelete  = "delete"
+	ChangeRead    = "read"
+)
+
+// ChangeActions lists actions in the order changes are grouped by
+var ChangeActions = []string{ChangeCreate, ChangeUpdate, ChangeReplace, ChangeDelete, ChangeRead}
+
+const (
+	// shown for values that are only known once applied
+	unknownValue = "(known after apply)"
+	// values longer than this many characters are truncated
+	maxAttributeValueLength = 80
+)
+
+// ResourceChange is a planned change of a single resource instance
+type ResourceChange struct {
+	Address string `json:"address"`
+	// module address, empty for the root module
+	Module   string `json:"module,omitempty"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Action   string `json:"action"`
+	// why the action was planned, eg. replace_because_tainted
+	Reason string `json:"reason,omitempty"`
+	// changed top level attributes, only resolved for updates and replacements
+	Attributes []*AttributeChange `json:"attributes,omitempty"`
+}
+
+// AttributeChange is a changed top level attribute of a resource, values are formatted
+// as JSON and sensitive values are masked
+type AttributeChange struct {
+	Name              string `json:"name"`
+	Before            string `json:"before"`
+	After             string `json:"after"`
+	Sensitive         bool   `json:"sensitive,omitempty"`
+	ForcesReplacement bool   `json:"forces_replacement,omitempty"`
+}
+
+// resource change representation of a JSON execution plan
+// https://developer.hashicorp.com/terraform/internals/json-format#change-representation
+type jsonPlanResourceChange struct {
+	Address       string `json:"address"`
+	ModuleAddress string `json:"module_address"`
+	Mode          string `json:"mode"`
+	Type          string `json:"type"`
+	Name          string `json:"name"`
+	ProviderName  string `json:"provider_name"`
+	ActionReason  string `json:"action_reason"`
+	Change        struct {
+		Actions         []string        `json:"actions"`
+		Before          interface{}     `json:"before"`
+		After           interface{}     `json:"after"`
+		AfterUnknown    interface{}     `json:"after_unknown"`
+		BeforeSensitive interface{}     `json:"before_sensitive"`
+		AfterSensitive  interface{}     `json:"after_sensitive"`
+		ReplacePaths    [][]interface{} `json:"replace_paths"`
+	} `json:"change"`
+}
+
+// ParsePlanChanges returns the resource changes of a JSON execution plan, sorted by action, module and address.
+// The plan must not be redacted, as changes of sensitive values cannot be detected otherwise.
+func ParsePlanChanges(data []byte) ([]*ResourceChange, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	plan := struct {
+		ResourceChanges []*jsonPlanResourceChange `json:"resource_changes"`
+	}{}
+	if err := decoder.Decode(&plan); err != nil {
+		return nil, fmt.Errorf("invalid JSON execution plan: %w", err)
+	}
+
+	changes := []*ResourceChange{}
+	for _, rc := range plan.ResourceChanges {
+		action := resolveChangeAction(rc.Change.Actions)
+		if action == "" {
+			continue
+		}
+		change := &ResourceChange{
+			Address:  rc.Address,
+			Module:   rc.ModuleAddress,
+			Type:     rc.Type,
+			Name:     rc.Name,
+			Provider: strings.TrimPrefix(rc.ProviderName, "registry.terraform.io/"),
+			Action:   action,
+			Reason:   rc.ActionReason,
+		}
+		if action == ChangeUpdate || action == ChangeReplace {
+			change.Attributes = changedAttributes(rc)
+		}
+		changes = append(changes, change)
+	}
+
+	order := map[string]int{}
+	for i, a := range ChangeActions {
+		order[a] = i
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Action != changes[j].Action {
+			return order[changes[i].Action] < order[changes[j].Action]
+		}
+		if changes[i].Module != changes[j].Module {
+			return changes[i].Module < changes[j].Module
+		}
+		return changes[i].Address < changes[j].Address
+	})
+	return changes, nil
+}
+
+// resolves the action of an action list, returns empty for no-op changes
+func resolveChangeAction(actions []string) string {
+	switch len(actions) {
+	case 1:
+		switch actions[0] {
+		case ChangeCreate, ChangeUpdate, ChangeDelete, ChangeRead:
+			return actions[0]
+		}
+	case 2:
+		// create-before-destroy or destroy-before-create
+		return ChangeReplace
+	}
+	return ""
+}
+
+// compares top level attributes of the before and after values
+func changedAttributes(rc *jsonPlanResourceChange) []*AttributeChange {
+	before, _ := rc.Change.Before.(map[string]interface{})
+	after, _ := rc.Change.After.(map[string]interface{})
+	afterUnknown, _ := rc.Change.AfterUnknown.(map[string]interface{})
+	beforeSensitive, _ := rc.Change.BeforeSensitive.(map[string]interface{})
+	afterSensitive, _ := rc.Change.AfterSensitive.(map[string]interface{})
+
+	forcesReplacement := map[string]bool{}
+	for _, path := range rc.Change.ReplacePaths {
+		if len(path) > 0 {
+			if name, ok := path[0].(string); ok {
+				forcesReplacement[name] = true
+			}
+		}
+	}
+
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	for name := range afterUnknown {
+		names[name] = true
+	}
+
+	attributes := []*AttributeChange{}
+	for name := range names {
+		unknown := containsTrue(afterUnknown[name])
+		if !unknown && reflect.DeepEqual(before[name], after[name]) {
+			continue
+		}
+		attr := &AttributeChange{
+			Name:              name,
+			Sensitive:         containsTrue(beforeSensitive[name]) || containsTrue(afterSensitive[name]),
+			ForcesReplacement: forcesReplacement[name],
+		}
+		if attr.Sensitive {
+			attr.Before, attr.After = redactedValue, redactedValue
+		} else {
+			attr.Before = formatAttributeValue(before[name])
+			attr.After = formatAttributeValue(after[name])
+		}
+		if unknown {
+			attr.After = unknownValue
+		}
+		attributes = append(attributes, attr)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Name < attributes[j].Name
+	})
+	return attributes
+}
+
+// reports whether a sensitivity or unknown mask marks any nested value
+func containsTrue(mask interface{}) bool {
+	switch m := mask.(type) {
+	case bool:
+		return m
+	case map[string]interface{}:
+		for _, v := range m {
+			if containsTrue(v) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range m {
+			if containsTrue(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatAttributeValue(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	formatted := strings.TrimRight(buf.String(), "\n")
+	// truncate characters rather than bytes, to keep multi-byte characters intact
+	if runes := []rune(formatted); len(runes) > maxAttributeValueLength {
+		formatted = string(runes[:maxAttributeValueLength-3]) + "..."
+	}
+	return formatted
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// unredacted plan with a replaced database, a created subnet in a module, a deleted bucket and an unchanged resource
+const testPlanChangesJSON = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["delete"], "before": {"bucket": "logs"}, "after": null}
+    },
+    {
+      "address": "aws_db_instance.main",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "main",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "action_reason": "replace_because_cannot_update",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"engine_version": "13.4", "instance_class": "db.t3.micro", "password": "old", "id": "db-1"},
+        "after": {"engine_version": "15.2", "instance_class": "db.t3.micro", "password": "new"},
+        "after_unknown": {"id": true},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true},
+        "replace_paths": [["engine_version"]]
+      }
+    },
+    {
+      "address": "module.network.aws_subnet.a",
+      "module_address": "module.network",
+      "mode": "managed",
+      "type": "aws_subnet",
+      "name": "a",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["create"], "before": null, "after": {"cidr_block": "10.0.1.0/24"}, "after_unknown": {"id": true}}
+    },
+    {
+      "address": "random_pet.name",
+      "mode": "managed",
+      "type": "random_pet",
+      "name": "name",
+      "provider_name": "registry.terraform.io/hashicorp/random",
+      "change": {"actions": ["no-op"], "before": {"id": "sunny-dog"}, "after": {"id": "sunny-dog"}}
+    }
+  ]
+}`
+
+func TestParsePlanChanges(t *testing.T) {
+	changes, err := ParsePlanChanges([]byte(testPlanChangesJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []*ResourceChange{
+		{
+			Address:  "module.network.aws_subnet.a",
+			Module:   "module.network",
+			Type:     "aws_subnet",
+			Name:     "a",
+			Provider: "hashicorp/aws",
+			Action:   ChangeCreate,
+		},
+		{
+			Address:  "aws_db_instance.main",
+			Type:     "aws_db_instance",
+			Name:     "main",
+			Provider: "hashicorp/aws",
+			Action:   ChangeReplace,
+			Reason:   "replace_because_cannot_update",
+			Attributes: []*AttributeChange{
+				{Name: "engine_version", Before: `"13.4"`, After: `"15.2"`, ForcesReplacement: true},
+				{Name: "id", Before: `"db-1"`, After: unknownValue},
+				{Name: "password", Before: redactedValue, After: redactedValue, Sensitive: true},
+			},
+		},
+		{
+			Address:  "aws_s3_bucket.logs",
+			Type:     "aws_s3_bucket",
+			Name:     "logs",
+			Provider: "hashicorp/aws",
+			Action:   ChangeDelete,
+		},
+	}
+
+	if !reflect.DeepEqual(changes, expected) {
+		for i, c := range changes {
+			t.Logf("change %d: %+v", i, c)
+			for _, a := range c.Attributes {
+				t.Logf("  attribute: %+v", a)
+			}
+		}
+		t.Errorf("unexpected resource changes")
+	}
+}
+
+func TestParsePlanChanges_Invalid(t *testing.T) {
+	if _, err := ParsePlanChanges([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON execution plan")
+	}
+}
+
+func TestResolveChangeAction(t *testing.T) {
+	testCases := []struct {
+		actions  []string
+		expected string
+	}{
+		{actions: []string{"create"}, expected: ChangeCreate},
+		{actions: []string{"update"}, expected: ChangeUpdate},
+		{actions: []string{"delete"}, expected: ChangeDelete},
+		{actions: []string{"read"}, expected: ChangeRead},
+		{actions: []string{"create", "delete"}, expected: ChangeReplace},
+		{actions: []string{"delete", "create"}, expected: ChangeReplace},
+		{actions: []string{"no-op"}, expected: ""},
+		{actions: nil, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		if actual := resolveChangeAction(tc.actions); actual != tc.expected {
+			t.Errorf("expected %q for %v, but received: %q", tc.expected, tc.actions, actual)
+		}
+	}
+}
+
+func TestFormatAttributeValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "string", value: "eu-west-1", expected: `"eu-west-1"`},
+		{name: "non-ascii", value: "Größe", expected: `"Größe"`},
+		{name: "long", value: strings.Repeat("a", 100), expected: `"` + strings.Repeat("a", 76) + "..."},
+		{name: "long-non-ascii", value: strings.Repeat("é", 100), expected: `"` + strings.Repeat("é", 76) + "..."},
+		{name: "html", value: "<none>", expected: `"<none>"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := formatAttributeValue(tc.value); actual != tc.expected {
+				t.Errorf("expected %q, but received: %q", tc.expected, actual)
+			}
+		})
+	}
+}
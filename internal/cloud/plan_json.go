@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// replaces sensitive values of JSON execution plans
+const redactedValue = "(sensitive value)"
+
+// RedactPlanJSON replaces sensitive values of a JSON execution plan, following the sensitivity
+// masks of resource changes, resource values, outputs and variables declared as sensitive.
+// Constant values of the configuration expressions and sensitive variable defaults are
+// redacted as well.
+// https://developer.hashicorp.com/terraform/internals/json-format#plan-representation
+func RedactPlanJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep number precision of attribute values
+	decoder.UseNumber()
+
+	var plan map[string]interface{}
+	if err := decoder.Decode(&plan); err != nil {
+		return nil, fmt.Errorf("invalid JSON execution plan: %w", err)
+	}
+
+	for _, key := range []string{"resource_changes", "resource_drift"} {
+		for _, rc := range asSlice(plan[key]) {
+			redactChange(asMap(asMap(rc)["change"]))
+		}
+	}
+
+	for _, oc := range asMap(plan["output_changes"]) {
+		redactChange(asMap(oc))
+	}
+
+	redactValues(asMap(plan["planned_values"]))
+	redactValues(asMap(asMap(plan["prior_state"])["values"]))
+
+	configuration := asMap(plan["configuration"])
+	// variable values only carry the sensitive flag in the configuration
+	sensitiveVars := asMap(asMap(configuration["root_module"])["variables"])
+	for name, v := range asMap(plan["variables"]) {
+		variable := asMap(v)
+		if sensitive, _ := asMap(sensitiveVars[name])["sensitive"].(bool); sensitive && variable != nil {
+			variable["value"] = redactedValue
+		}
+	}
+
+	redactConfigModule(asMap(configuration["root_module"]))
+	// literals can't be told apart from sensitive values without evaluation
+	redactExpressions(configuration)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(plan); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// redacts before and after values of a change with its sensitivity masks
+func redactChange(change map[string]interface{}) {
+	if change == nil {
+		return
+	}
+	for _, key := range []string{"before", "after"} {
+		if value, ok := change[key]; ok {
+			change[key] = redactValue(value, change[key+"_sensitive"])
+		}
+	}
+}
+
+// redacts outputs and resource values of a state or planned values representation
+func redactValues(values map[string]interface{}) {
+	if values == nil {
+		return
+	}
+	for _, o := range asMap(values["outputs"]) {
+		output := asMap(o)
+		if sensitive, _ := output["sensitive"].(bool); sensitive {
+			output["value"] = redactedValue
+		}
+	}
+	redactModule(asMap(values["root_module"]))
+}
+
+func redactModule(module map[string]interface{}) {
+	if module == nil {
+		return
+	}
+	for _, r := range asSlice(module["resources"]) {
+		resource := asMap(r)
+		if value, ok := resource["values"]; ok {
+			resource["values"] = redactValue(value, resource["sensitive_values"])
+		}
+	}
+	for _, child := range asSlice(module["child_modules"]) {
+		redactModule(asMap(child))
+	}
+}
+
+// redacts defaults of sensitive variables of a configuration module and its module calls
+func redactConfigModule(module map[string]interface{}) {
+	if module == nil {
+		return
+	}
+	for _, v := range asMap(module["variables"]) {
+		variable := asMap(v)
+		if sensitive, _ := variable["sensitive"].(bool); sensitive {
+			if _, ok := variable["default"]; ok {
+				variable["default"] = redactedValue
+			}
+		}
+	}
+	for _, call := range asMap(module["module_calls"]) {
+		redactConfigModule(asMap(asMap(call)["module"]))
+	}
+}
+
+// replaces every `constant_value` of the expressions found in value
+func redactExpressions(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if k == "constant_value" {
+				v[k] = redactedValue
+				continue
+			}
+			redactExpressions(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactExpressions(child)
+		}
+	}
+}
+
+// replaces parts of value marked as sensitive by mask, the mask mirrors the structure of
+// the value with `true` for each sensitive value
+func redactValue(value interface{}, mask interface{}) interface{} {
+	switch m := mask.(type) {
+	case bool:
+		if m {
+			return redactedValue
+		}
+	case map[string]interface{}:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		for k, keyMask := range m {
+			if v, ok := obj[k]; ok {
+				obj[k] = redactValue(v, keyMask)
+			}
+		}
+	case []interface{}:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		for i := range list {
+			if i < len(m) {
+				list[i] = redactValue(list[i], m[i])
+			}
+		}
+	}
+	return value
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestRedactPlanJSON(t *testing.T) {
+	plan := `{
+  "format_version": "1.2",
+  "variables": {
+    "db_password": {"value": "hunter2"},
+    "region": {"value": "us-east-1"}
+  },
+  "planned_values": {
+    "outputs": {
+      "connection": {"sensitive": true, "value": "postgres://admin:hunter2@db"},
+      "endpoint": {"sensitive": false, "value": "db.example.com"}
+    },
+    "root_module": {
+      "child_modules": [{
+        "resources": [{
+          "address": "module.db.aws_db_instance.this",
+          "values": {"password": "hunter2", "port": 5432, "tags": {"team": "data"}},
+          "sensitive_values": {"password": true, "tags": {}}
+        }]
+      }]
+    }
+  },
+  "resource_changes": [{
+    "address": "module.db.aws_db_instance.this",
+    "change": {
+      "actions": ["update"],
+      "before": {"password": "hunter1", "users": ["admin", "app"], "port": 5432},
+      "after": {"password": "hunter2", "users": ["admin", "app"], "port": 12345678901234567890},
+      "before_sensitive": {"password": true, "users": [false, true]},
+      "after_sensitive": {"password": true, "users": [false, true]}
+    }
+  }],
+  "output_changes": {
+    "connection": {"actions": ["create"], "before": null, "after": "postgres://admin:hunter2@db", "before_sensitive": false, "after_sensitive": true}
+  },
+  "configuration": {
+    "root_module": {
+      "variables": {
+        "db_password": {"default": "hunter2", "sensitive": true},
+        "region": {"default": "us-east-1"}
+      },
+      "resources": [{
+        "address": "aws_db_instance.this",
+        "expressions": {
+          "password": {"constant_value": "hunter2"},
+          "engine": {"references": ["var.region"]},
+          "tags": [{"team": {"constant_value": "data"}}]
+        }
+      }],
+      "module_calls": {
+        "db": {
+          "source": "./db",
+          "expressions": {"password": {"constant_value": "hunter2"}},
+          "module": {
+            "variables": {"password": {"default": "hunter2", "sensitive": true}},
+            "outputs": {"connection": {"expression": {"constant_value": "postgres://admin:hunter2@db"}}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+	data, err := RedactPlanJSON([]byte(plan))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var actual map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&actual); err != nil {
+		t.Fatalf("invalid redacted plan: %s", err)
+	}
+
+	assertValue := func(name string, value interface{}, expected string) {
+		t.Helper()
+		actual, _ := json.Marshal(value)
+		if string(actual) != expected {
+			t.Errorf("%s: expected %s, but received: %s", name, expected, actual)
+		}
+	}
+
+	variables := asMap(actual["variables"])
+	assertValue("sensitive variable", asMap(variables["db_password"])["value"], `"(sensitive value)"`)
+	assertValue("variable", asMap(variables["region"])["value"], `"us-east-1"`)
+
+	planned := asMap(actual["planned_values"])
+	assertValue("sensitive output", asMap(asMap(planned["outputs"])["connection"])["value"], `"(sensitive value)"`)
+	assertValue("output", asMap(asMap(planned["outputs"])["endpoint"])["value"], `"db.example.com"`)
+	resource := asMap(asSlice(asMap(asSlice(asMap(planned["root_module"])["child_modules"])[0])["resources"])[0])
+	assertValue("resource values", resource["values"], `{"password":"(sensitive value)","port":5432,"tags":{"team":"data"}}`)
+
+	change := asMap(asMap(asSlice(actual["resource_changes"])[0])["change"])
+	assertValue("before", change["before"], `{"password":"(sensitive value)","port":5432,"users":["admin","(sensitive value)"]}`)
+	assertValue("after", change["after"], `{"password":"(sensitive value)","port":12345678901234567890,"users":["admin","(sensitive value)"]}`)
+
+	outputChange := asMap(asMap(actual["output_changes"])["connection"])
+	assertValue("output change before", outputChange["before"], `null`)
+	assertValue("output change after", outputChange["after"], `"(sensitive value)"`)
+
+	rootModule := asMap(asMap(actual["configuration"])["root_module"])
+	configVars := asMap(rootModule["variables"])
+	assertValue("sensitive variable default", asMap(configVars["db_password"])["default"], `"(sensitive value)"`)
+	assertValue("variable default", asMap(configVars["region"])["default"], `"us-east-1"`)
+	expressions := asMap(asSlice(rootModule["resources"])[0])["expressions"]
+	assertValue("resource expressions", expressions, `{"engine":{"references":["var.region"]},"password":{"constant_value":"(sensitive value)"},"tags":[{"team":{"constant_value":"(sensitive value)"}}]}`)
+	call := asMap(asMap(rootModule["module_calls"])["db"])
+	assertValue("module call expressions", call["expressions"], `{"password":{"constant_value":"(sensitive value)"}}`)
+	module := asMap(call["module"])
+	assertValue("module variable default", asMap(asMap(module["variables"])["password"])["default"], `"(sensitive value)"`)
+	assertValue("module output expression", asMap(asMap(module["outputs"])["connection"])["expression"], `{"constant_value":"(sensitive value)"}`)
+}
+
+func TestRedactPlanJSON_Invalid(t *testing.T) {
+	if _, err := RedactPlanJSON([]byte("not json")); err == nil {
+		t.Error("expected error for invalid plan")
+	}
+}
@@ -20,9 +20,9 @@ const LogTimeout = time.Second * 10
 
 var (
 	ForceCancel              = tfe.RunStatus("force_canceled")
-	PrePlanAwaitingDecision  = tfe.RunStatus("pre_apply_awaiting_decision")
+	PrePlanAwaitingDecision  = tfe.RunStatus("pre_plan_awaiting_decision")
 	PostPlanAwaitingDecision = tfe.RunStatus("post_plan_awaiting_decision")
-	PreApplyAwaitingDecision = tfe.RunStatus("pre_plan_awaiting_decision")
+	PreApplyAwaitingDecision = tfe.RunStatus("pre_apply_awaiting_decision")
 )
 
 var DiscardNoopStatus = []tfe.RunStatus{
@@ -52,6 +52,9 @@ var NoopStatus = []tfe.RunStatus{
 	PreApplyAwaitingDecision,
 }
 
+// RunStatusChangeFunc is called from polling loops each time a run is observed with a new status
+type RunStatusChangeFunc func(run *tfe.Run)
+
 type CreateRunOptions struct {
 	Organization           string
 	Workspace              string
@@ -63,11 +66,17 @@ type CreateRunOptions struct {
 	SavePlan               bool
 	RunVariables           []*tfe.RunVariable
 	TargetAddrs            []string
+	// returns once the run is created, without waiting for it to complete
+	NoWait bool
+	// phase the run is waited for, defaults to a phase inferred from auto-apply, cost estimation and policy checks
+	WaitUntil      RunPhase
+	OnStatusChange RunStatusChangeFunc
 }
 
 type ApplyRunOptions struct {
-	RunID   string
-	Comment string
+	RunID          string
+	Comment        string
+	OnStatusChange RunStatusChangeFunc
 }
 
 type GetRunOptions struct {
@@ -85,6 +94,11 @@ type CancelRunOptions struct {
 	ForceCancel bool
 }
 
+type CommentRunOptions struct {
+	RunID string
+	Body  string
+}
+
 type RunService interface {
 	RunLink(context.Context, string, *tfe.Run) (string, error)
 	GetRun(context.Context, GetRunOptions) (*tfe.Run, error)
@@ -92,11 +106,19 @@ type RunService interface {
 	ApplyRun(context.Context, ApplyRunOptions) (*tfe.Run, error)
 	DiscardRun(context.Context, DiscardRunOptions) (*tfe.Run, error)
 	CancelRun(context.Context, CancelRunOptions) (*tfe.Run, error)
-	GetPlanLogs(context.Context, string) error
-	GetApplyLogs(context.Context, string) error
+	CommentRun(context.Context, CommentRunOptions) error
+	WatchRun(context.Context, WatchRunOptions) (*tfe.Run, error)
+	WaitRun(context.Context, WaitRunOptions) (*tfe.Run, error)
+	ListRuns(context.Context, ListRunsOptions) ([]*tfe.Run, error)
+	SupersedeRuns(context.Context, SupersedeRunsOptions) ([]*tfe.Run, error)
+	GetPlanLogs(context.Context, string) (*RunLog, error)
+	GetApplyLogs(context.Context, string) (*RunLog, error)
+	StreamPlanLogs(context.Context, string) *LogStream
+	StreamApplyLogs(context.Context, string) *LogStream
 	GetPolicyCheckLogs(context.Context, *tfe.Run) error
 	LogCostEstimation(context.Context, *tfe.Run)
 	LogTaskStage(context.Context, *tfe.Run, tfe.Stage) error
+	ReadTaskStages(context.Context, *tfe.Run) ([]*tfe.TaskStage, error)
 }
 
 type runService struct {
@@ -119,7 +141,7 @@ func (service *runService) RunLink(ctx context.Context, organization string, run
 
 func (service *runService) GetRun(ctx context.Context, options GetRunOptions) (*tfe.Run, error) {
 	run, err := service.tfe.Runs.ReadWithOptions(ctx, options.RunID, &tfe.RunReadOptions{
-		Include: []tfe.RunIncludeOpt{"cost_estimate", "plan"},
+		Include: []tfe.RunIncludeOpt{"cost_estimate", "plan", "apply", "workspace", "configuration_version"},
 	})
 	if err != nil {
 		log.Printf("[ERROR] error reading run: %q error: %s", options.RunID, err)
@@ -176,42 +198,14 @@ func (service *runService) CreateRun(ctx context.Context, options CreateRunOptio
 
 	service.writer.Output(fmt.Sprintf("Created Run ID: %q", run.ID))
 
-	costEstimateEnabled, policyChecksEnabled := hasCostEstimate(run), hasPolicyChecks(run)
-	desiredStatus := getDesiredRunStatus(run, policyChecksEnabled, costEstimateEnabled)
-
-	log.Printf("[DEBUG] PlanOnly: %t, AutoApply: %t, CostEstimation: %t, PolicyChecks: %t", run.PlanOnly, run.AutoApply, costEstimateEnabled, policyChecksEnabled)
-
-	retryErr := retry.Do(ctx, defaultBackoff(), func(ctx context.Context) error {
-		log.Printf("[DEBUG] Monitoring run status...")
-		r, err := service.GetRun(ctx, GetRunOptions{
-			RunID: run.ID,
-		})
-
-		// update run
-		run = r
-
-		if err != nil {
-			return err
-		}
-
-		service.writer.Output(fmt.Sprintf("Run Status: %q", run.Status))
-
-		done, err := isRunComplete(r, desiredStatus, NoopStatus)
-		if err != nil {
-			return err
-		}
-
-		if done {
-			return nil
-		}
-		return retryableTimeoutError("create run ")
-	})
-
-	if retryErr != nil {
-		return run, retryErr
+	if options.NoWait {
+		return run, nil
 	}
 
-	return run, nil
+	notifyStatusChange := statusChangeNotifier(options.OnStatusChange)
+	notifyStatusChange(run)
+
+	return service.pollRun(ctx, run, untilReached(run, options.WaitUntil), notifyStatusChange, "create run ")
 }
 
 func (service *runService) ApplyRun(ctx context.Context, options ApplyRunOptions) (*tfe.Run, error) {
@@ -223,6 +217,8 @@ func (service *runService) ApplyRun(ctx context.Context, options ApplyRunOptions
 		return applyRun, err
 	}
 
+	notifyStatusChange := statusChangeNotifier(options.OnStatusChange)
+
 	if retryErr := retry.Do(ctx, defaultBackoff(), func(ctx context.Context) error {
 		log.Printf("[DEBUG] Monitoring apply run status...")
 
@@ -237,6 +233,7 @@ func (service *runService) ApplyRun(ctx context.Context, options ApplyRunOptions
 		}
 
 		service.writer.Output(fmt.Sprintf("Run Status: %q", run.Status))
+		notifyStatusChange(run)
 
 		done, err := isRunComplete(run, []tfe.RunStatus{tfe.RunApplied}, NoopStatus)
 		if err != nil {
@@ -254,6 +251,17 @@ func (service *runService) ApplyRun(ctx context.Context, options ApplyRunOptions
 	return applyRun, nil
 }
 
+// posts a comment to the run, shown in the run timeline of HCP Terraform
+func (service *runService) CommentRun(ctx context.Context, options CommentRunOptions) error {
+	if _, err := service.tfe.Comments.Create(ctx, options.RunID, tfe.CommentCreateOptions{
+		Body: options.Body,
+	}); err != nil {
+		log.Printf("[ERROR] error commenting run: %q error: %s", options.RunID, err.Error())
+		return err
+	}
+	return nil
+}
+
 func (service *runService) DiscardRun(ctx context.Context, options DiscardRunOptions) (*tfe.Run, error) {
 	var discardRun *tfe.Run
 	if err := service.tfe.Runs.Discard(ctx, options.RunID, tfe.RunDiscardOptions{
@@ -342,7 +350,7 @@ func (service *runService) CancelRun(ctx context.Context, options CancelRunOptio
 	return cancelRun, nil
 }
 
-func (service *runService) GetPlanLogs(ctx context.Context, planID string) error {
+func (service *runService) GetPlanLogs(ctx context.Context, planID string) (*RunLog, error) {
 	ctxTimeout, cancel := context.WithTimeout(ctx, LogTimeout)
 	defer cancel()
 
@@ -350,19 +358,19 @@ func (service *runService) GetPlanLogs(ctx context.Context, planID string) error
 	var logReader io.Reader
 	logReader, err = service.tfe.Plans.Logs(ctxTimeout, planID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	service.writer.Output(fmt.Sprintf("-------------- %s --------------", "Plan Log"))
-	err = outputRunLogLines(logReader, service.writer)
+	runLog, err := outputRunLogLines(logReader, service.writer, service.logOptions)
 	if err != nil {
-		return err
+		return runLog, err
 	}
 	fmt.Println()
-	return nil
+	return runLog, nil
 }
 
-func (service *runService) GetApplyLogs(ctx context.Context, applyID string) error {
+func (service *runService) GetApplyLogs(ctx context.Context, applyID string) (*RunLog, error) {
 	ctxTimeout, cancel := context.WithTimeout(ctx, LogTimeout)
 	defer cancel()
 
@@ -370,16 +378,16 @@ func (service *runService) GetApplyLogs(ctx context.Context, applyID string) err
 	var logReader io.Reader
 	logReader, err = service.tfe.Applies.Logs(ctxTimeout, applyID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	service.writer.Output(fmt.Sprintf("-------------- %s --------------", "Apply Log"))
-	err = outputRunLogLines(logReader, service.writer)
+	runLog, err := outputRunLogLines(logReader, service.writer, service.logOptions)
 	if err != nil {
-		return err
+		return runLog, err
 	}
 	fmt.Println()
-	return nil
+	return runLog, nil
 }
 
 func (s *runService) GetPolicyCheckLogs(ctx context.Context, run *tfe.Run) error {
@@ -416,7 +424,8 @@ func (s *runService) GetPolicyCheckLogs(ctx context.Context, run *tfe.Run) error
 			logStart = false
 		}
 
-		err = outputRunLogLines(logReader, s.writer)
+		// sentinel logs are not structured
+		_, err = outputRunLogLines(logReader, s.writer, LogOptions{View: LogViewRaw})
 		if err != nil {
 			return err
 		}
@@ -467,6 +476,26 @@ func (s *runService) LogTaskStage(ctx context.Context, run *tfe.Run, stage tfe.S
 	return nil
 }
 
+// returns run task stages including their task results
+func (s *runService) ReadTaskStages(ctx context.Context, run *tfe.Run) ([]*tfe.TaskStage, error) {
+	taskStages, err := s.tfe.TaskStages.List(ctx, run.ID, &tfe.TaskStageListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	stages := []*tfe.TaskStage{}
+	for _, stage := range taskStages.Items {
+		detail, readErr := s.tfe.TaskStages.Read(ctx, stage.ID, &tfe.TaskStageReadOptions{
+			Include: []tfe.TaskStageIncludeOpt{tfe.TaskStageTaskResults},
+		})
+		if readErr != nil {
+			return nil, fmt.Errorf("error reading task stage: %s", readErr.Error())
+		}
+		stages = append(stages, detail)
+	}
+	return stages, nil
+}
+
 func (s *runService) LogCostEstimation(ctx context.Context, run *tfe.Run) {
 	if run.CostEstimate == nil || run.CostEstimate.Status == tfe.CostEstimateStatus("unreachable") || run.CostEstimate.Status == tfe.CostEstimatePending {
 		return
@@ -478,8 +507,9 @@ func (s *runService) LogCostEstimation(ctx context.Context, run *tfe.Run) {
 	fmt.Println()
 }
 
-func outputRunLogLines(logs io.Reader, writer Writer) error {
+func outputRunLogLines(logs io.Reader, writer Writer, opts LogOptions) (*RunLog, error) {
 	var err error
+	runLog := &RunLog{}
 	reader := bufio.NewReaderSize(logs, 64*1024)
 	for next := true; next; {
 		var l, line []byte
@@ -488,7 +518,7 @@ func outputRunLogLines(logs io.Reader, writer Writer) error {
 			l, isPrefix, err = reader.ReadLine()
 			if err != nil {
 				if err != io.EOF {
-					return err
+					return runLog, err
 				}
 				next = false
 			}
@@ -496,61 +526,29 @@ func outputRunLogLines(logs io.Reader, writer Writer) error {
 		}
 
 		if next || len(line) > 0 {
-			writer.Output(string(line))
+			msg := runLog.parseLine(line)
+			for _, out := range opts.render(line, msg) {
+				writer.Output(out)
+			}
 		}
 	}
-	return nil
+	return runLog, nil
 }
 
 func NewRunService(meta *cloudMeta) RunService {
 	return &runService{meta}
 }
 
-func getDesiredRunStatus(run *tfe.Run, policyChecksEnabled bool, costEstimateEnabled bool) []tfe.RunStatus {
-	// shared desired status across all runs
-	desiredStatus := []tfe.RunStatus{
-		tfe.RunPolicySoftFailed,
-		tfe.RunPlannedAndFinished,
-		tfe.RunApplied,
-		tfe.RunPostPlanCompleted,
-	}
-
-	if run.SavePlan {
-		desiredStatus = append(desiredStatus, tfe.RunPlannedAndSaved)
-	}
-
-	// when plan_only run
-	if run.PlanOnly {
-		// plan only runs will result in default desired slice
-		// most likely planned_and_finished or policy_soft_failed
-		return desiredStatus
-	}
-
-	// when auto_apply run
-	if run.AutoApply {
-		if policyChecksEnabled {
-			// policy override requires human approval before proceeding, run has reached no-op
-			desiredStatus = append(desiredStatus, tfe.RunPolicyOverride)
+// wraps callback so it is only called when the observed run status differs from the previous one
+func statusChangeNotifier(callback RunStatusChangeFunc) RunStatusChangeFunc {
+	var lastStatus tfe.RunStatus
+	return func(run *tfe.Run) {
+		if callback == nil || run == nil || run.Status == lastStatus {
+			return
 		}
-		return desiredStatus
-	}
-
-	// when applyable/confirmable run
-	// determine which various run status it can end with
-	if costEstimateEnabled && !policyChecksEnabled {
-		// cost_estimation executes prior to sentinel policies
-		// if we expect `"cost_estimated"` as a final step, the cmd will eject too early
-		desiredStatus = append(desiredStatus, tfe.RunCostEstimated)
-	} else if policyChecksEnabled {
-		// account for `"policy_checked"` & `"policy_override"` if policy checks are enabled for the run
-		desiredStatus = append(desiredStatus, tfe.RunPolicyChecked, tfe.RunPolicyOverride)
-	} else {
-		// applyable/confirmable run has no cost estimation nor sentinel policies
-		// run task stages are accounted for in the Noop RunStatus slice
-		desiredStatus = append(desiredStatus, tfe.RunPlanned)
+		lastStatus = run.Status
+		callback(run)
 	}
-
-	return desiredStatus
 }
 
 func isRunComplete(run *tfe.Run, desiredStatus []tfe.RunStatus, noopStatus []tfe.RunStatus) (done bool, err error) {
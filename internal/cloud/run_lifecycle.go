@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+// RunPhase is a phase of the run lifecycle that polling a run stops at
+type RunPhase string
+
+const (
+	// the plan finished
+	RunPhasePlanned RunPhase = "planned"
+	// post-plan run tasks completed
+	RunPhasePostPlanCompleted RunPhase = "post_plan_completed"
+	// the cost estimation finished
+	RunPhaseCostEstimated RunPhase = "cost_estimated"
+	// policies were evaluated
+	RunPhasePolicyChecked RunPhase = "policy_checked"
+	// the run awaits confirmation or was confirmed
+	RunPhaseConfirmable RunPhase = "confirmable"
+	// the run applied or finished without changes to apply
+	RunPhaseApplied RunPhase = "applied"
+	// the run applied, finished without changes to apply or saved its plan
+	RunPhaseCompleted RunPhase = "completed"
+)
+
+// phases in the order runs pass them, stages that are not enabled for a workspace are skipped
+var runPhases = []RunPhase{
+	RunPhasePlanned,
+	RunPhasePostPlanCompleted,
+	RunPhaseCostEstimated,
+	RunPhasePolicyChecked,
+	RunPhaseConfirmable,
+	RunPhaseApplied,
+	RunPhaseCompleted,
+}
+
+// ParseRunPhase validates the run phase, defaults to completed
+func ParseRunPhase(v string) (RunPhase, error) {
+	if v == "" {
+		return RunPhaseCompleted, nil
+	}
+	for _, phase := range runPhases {
+		if RunPhase(v) == phase {
+			return phase, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported run phase: %q, expected one of: %v", v, runPhases)
+}
+
+// position of the phase in the run lifecycle, -1 before the plan finished
+func (phase RunPhase) order() int {
+	for i, p := range runPhases {
+		if p == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+type runState int
+
+const (
+	// the run progresses, or waits for confirmation
+	runStateActive runState = iota
+	// the run waits for an override of failed policies or run tasks, later phases are not reached without it
+	runStateAwaitingDecision
+	// the saved plan can be confirmed later, the run is not applied
+	runStateSaved
+	// the run is done, every phase is reached
+	runStateFinished
+	// the run ended without completing
+	runStateEnded
+)
+
+type runLifecycleStatus struct {
+	// furthest phase reached with the status, empty before the plan finished
+	phase RunPhase
+	state runState
+}
+
+// every run status, statuses missing from the table are rejected instead of polled
+var runLifecycle = map[tfe.RunStatus]runLifecycleStatus{
+	tfe.RunPending:                  {},
+	tfe.RunFetching:                 {},
+	tfe.RunFetchingCompleted:        {},
+	tfe.RunQueuing:                  {},
+	tfe.RunPlanQueued:               {},
+	tfe.RunPrePlanRunning:           {},
+	tfe.RunPrePlanCompleted:         {},
+	PrePlanAwaitingDecision:         {state: runStateAwaitingDecision},
+	tfe.RunPlanning:                 {},
+	tfe.RunPlanned:                  {phase: RunPhasePlanned},
+	tfe.RunPostPlanRunning:          {phase: RunPhasePlanned},
+	tfe.RunPostPlanAwaitingDecision: {phase: RunPhasePlanned, state: runStateAwaitingDecision},
+	tfe.RunPostPlanCompleted:        {phase: RunPhasePostPlanCompleted},
+	tfe.RunCostEstimating:           {phase: RunPhasePostPlanCompleted},
+	tfe.RunCostEstimated:            {phase: RunPhaseCostEstimated},
+	tfe.RunPolicyChecking:           {phase: RunPhaseCostEstimated},
+	tfe.RunPolicyChecked:            {phase: RunPhasePolicyChecked},
+	tfe.RunPolicyOverride:           {phase: RunPhasePolicyChecked, state: runStateAwaitingDecision},
+	tfe.RunPolicySoftFailed:         {phase: RunPhasePolicyChecked, state: runStateAwaitingDecision},
+	tfe.RunConfirmed:                {phase: RunPhaseConfirmable},
+	tfe.RunApplyQueued:              {phase: RunPhaseConfirmable},
+	tfe.RunQueuingApply:             {phase: RunPhaseConfirmable},
+	tfe.RunPreApplyRunning:          {phase: RunPhaseConfirmable},
+	tfe.RunPreApplyCompleted:        {phase: RunPhaseConfirmable},
+	PreApplyAwaitingDecision:        {phase: RunPhaseConfirmable, state: runStateAwaitingDecision},
+	tfe.RunApplying:                 {phase: RunPhaseConfirmable},
+	tfe.RunPlannedAndSaved:          {phase: RunPhaseConfirmable, state: runStateSaved},
+	tfe.RunPlannedAndFinished:       {state: runStateFinished},
+	tfe.RunApplied:                  {state: runStateFinished},
+	tfe.RunErrored:                  {state: runStateEnded},
+	tfe.RunCanceled:                 {state: runStateEnded},
+	tfe.RunDiscarded:                {state: runStateEnded},
+	ForceCancel:                     {state: runStateEnded},
+}
+
+// reports whether the run reached the phase, runs that ended or await a decision before it,
+// or have an unknown status are returned as error
+func (phase RunPhase) reached(run *tfe.Run) (bool, error) {
+	status, err := lifecycleStatus(run)
+	if err != nil {
+		return true, err
+	}
+
+	switch status.state {
+	case runStateEnded:
+		return true, fmt.Errorf("run has ended with: '%s' status", run.Status)
+	case runStateFinished:
+		return true, nil
+	case runStateAwaitingDecision:
+		if phase.order() <= status.phase.order() {
+			return true, nil
+		}
+		return true, fmt.Errorf("run is awaiting a decision with: '%s' status, an override is required to continue", run.Status)
+	case runStateSaved:
+		if phase == RunPhaseApplied {
+			return true, fmt.Errorf("run has ended with: '%s' status, the saved plan must be confirmed to apply it", run.Status)
+		}
+		return true, nil
+	}
+
+	if phase == RunPhaseCompleted {
+		return false, nil
+	}
+	// confirmable once the plan and every enabled check finished
+	if phase == RunPhaseConfirmable && run.Actions != nil && run.Actions.IsConfirmable {
+		return true, nil
+	}
+	return phase.order() <= status.phase.order(), nil
+}
+
+// the phase run create stops at without -wait-until: plan-only runs until completed, auto-apply runs until applied
+// and other runs once the last enabled check finished, or once they can be confirmed
+func defaultRunPhase(run *tfe.Run) RunPhase {
+	switch {
+	case run.PlanOnly:
+		return RunPhaseCompleted
+	case run.AutoApply:
+		return RunPhaseApplied
+	case hasPolicyChecks(run):
+		return RunPhasePolicyChecked
+	case hasCostEstimate(run):
+		// cost estimation runs before policy checks, it is only the last check without them
+		return RunPhaseCostEstimated
+	default:
+		return RunPhaseConfirmable
+	}
+}
+
+// looks up the run status, statuses missing from the run lifecycle fail instead of being polled until timeout
+func lifecycleStatus(run *tfe.Run) (runLifecycleStatus, error) {
+	status, ok := runLifecycle[run.Status]
+	if !ok {
+		return status, fmt.Errorf("unsupported run status: '%s', the run lifecycle does not include it", run.Status)
+	}
+	return status, nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+func TestRunLifecycle_AllStatuses(t *testing.T) {
+	// every status of go-tfe, a status added upstream must be placed in the run lifecycle
+	statuses := []tfe.RunStatus{
+		tfe.RunApplied,
+		tfe.RunApplying,
+		tfe.RunApplyQueued,
+		tfe.RunCanceled,
+		tfe.RunConfirmed,
+		tfe.RunCostEstimated,
+		tfe.RunCostEstimating,
+		tfe.RunDiscarded,
+		tfe.RunErrored,
+		tfe.RunFetching,
+		tfe.RunFetchingCompleted,
+		tfe.RunPending,
+		tfe.RunPlanned,
+		tfe.RunPlannedAndFinished,
+		tfe.RunPlannedAndSaved,
+		tfe.RunPlanning,
+		tfe.RunPlanQueued,
+		tfe.RunPolicyChecked,
+		tfe.RunPolicyChecking,
+		tfe.RunPolicyOverride,
+		tfe.RunPolicySoftFailed,
+		tfe.RunPostPlanAwaitingDecision,
+		tfe.RunPostPlanCompleted,
+		tfe.RunPostPlanRunning,
+		tfe.RunPreApplyRunning,
+		tfe.RunPreApplyCompleted,
+		tfe.RunPrePlanCompleted,
+		tfe.RunPrePlanRunning,
+		tfe.RunQueuing,
+		tfe.RunQueuingApply,
+		ForceCancel,
+		PrePlanAwaitingDecision,
+		PreApplyAwaitingDecision,
+	}
+	for _, status := range statuses {
+		if _, ok := runLifecycle[status]; !ok {
+			t.Errorf("expected status %q in the run lifecycle", status)
+		}
+	}
+	if len(runLifecycle) != len(statuses) {
+		t.Errorf("expected %d statuses in the run lifecycle, but received: %d", len(statuses), len(runLifecycle))
+	}
+}
+
+func TestRunPhase_Reached(t *testing.T) {
+	testCases := []struct {
+		name     string
+		phase    RunPhase
+		run      *tfe.Run
+		reached  bool
+		expected bool
+	}{
+		{name: "planning", phase: RunPhasePlanned, run: &tfe.Run{Status: tfe.RunPlanning}},
+		{name: "planned", phase: RunPhasePlanned, run: &tfe.Run{Status: tfe.RunPlanned}, reached: true},
+		// planned no longer waits for checks, confirmable does
+		{name: "cost-estimated-planned", phase: RunPhasePlanned, run: &tfe.Run{Status: tfe.RunCostEstimated, Actions: &tfe.RunActions{}}, reached: true},
+		{name: "planned-cost-estimated", phase: RunPhaseCostEstimated, run: &tfe.Run{Status: tfe.RunPlanned}},
+		{name: "post-plan-running", phase: RunPhasePostPlanCompleted, run: &tfe.Run{Status: tfe.RunPostPlanRunning}},
+		{name: "post-plan-completed", phase: RunPhasePostPlanCompleted, run: &tfe.Run{Status: tfe.RunPostPlanCompleted}, reached: true},
+		{name: "cost-estimating", phase: RunPhaseCostEstimated, run: &tfe.Run{Status: tfe.RunCostEstimating}},
+		{name: "cost-estimated", phase: RunPhaseCostEstimated, run: &tfe.Run{Status: tfe.RunCostEstimated}, reached: true},
+		{name: "cost-estimation-skipped", phase: RunPhaseCostEstimated, run: &tfe.Run{Status: tfe.RunPolicyChecked}, reached: true},
+		{name: "policy-checking", phase: RunPhasePolicyChecked, run: &tfe.Run{Status: tfe.RunPolicyChecking}},
+		{name: "policy-override", phase: RunPhasePolicyChecked, run: &tfe.Run{Status: tfe.RunPolicyOverride}, reached: true},
+		{name: "policy-override-confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunPolicyOverride}, reached: true, expected: true},
+		{name: "policy-soft-failed", phase: RunPhasePolicyChecked, run: &tfe.Run{Status: tfe.RunPolicySoftFailed}, reached: true},
+		{name: "policy-soft-failed-confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunPolicySoftFailed}, reached: true, expected: true},
+		{name: "policy-soft-failed-applied", phase: RunPhaseApplied, run: &tfe.Run{Status: tfe.RunPolicySoftFailed}, reached: true, expected: true},
+		{name: "pre-plan-awaiting-decision", phase: RunPhasePlanned, run: &tfe.Run{Status: PrePlanAwaitingDecision}, reached: true, expected: true},
+		{name: "post-plan-awaiting-decision-planned", phase: RunPhasePlanned, run: &tfe.Run{Status: tfe.RunPostPlanAwaitingDecision}, reached: true},
+		{name: "post-plan-awaiting-decision-confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunPostPlanAwaitingDecision}, reached: true, expected: true},
+		{name: "pre-apply-awaiting-decision-applied", phase: RunPhaseApplied, run: &tfe.Run{Status: PreApplyAwaitingDecision}, reached: true, expected: true},
+		{name: "cost-estimated-not-confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunCostEstimated, Actions: &tfe.RunActions{}}},
+		{name: "confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunPolicyChecked, Actions: &tfe.RunActions{IsConfirmable: true}}, reached: true},
+		{name: "applying-confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunApplying}, reached: true},
+		{name: "applying-applied", phase: RunPhaseApplied, run: &tfe.Run{Status: tfe.RunApplying}},
+		{name: "applying-completed", phase: RunPhaseCompleted, run: &tfe.Run{Status: tfe.RunApplying}},
+		{name: "confirmable-completed", phase: RunPhaseCompleted, run: &tfe.Run{Status: tfe.RunPlanned, Actions: &tfe.RunActions{IsConfirmable: true}}},
+		{name: "planned-and-finished-applied", phase: RunPhaseApplied, run: &tfe.Run{Status: tfe.RunPlannedAndFinished}, reached: true},
+		{name: "planned-and-finished", phase: RunPhaseCompleted, run: &tfe.Run{Status: tfe.RunPlannedAndFinished}, reached: true},
+		{name: "applied", phase: RunPhaseApplied, run: &tfe.Run{Status: tfe.RunApplied}, reached: true},
+		{name: "saved-confirmable", phase: RunPhaseConfirmable, run: &tfe.Run{Status: tfe.RunPlannedAndSaved}, reached: true},
+		{name: "saved-completed", phase: RunPhaseCompleted, run: &tfe.Run{Status: tfe.RunPlannedAndSaved}, reached: true},
+		{name: "saved-applied", phase: RunPhaseApplied, run: &tfe.Run{Status: tfe.RunPlannedAndSaved}, reached: true, expected: true},
+		{name: "discarded", phase: RunPhaseCompleted, run: &tfe.Run{Status: tfe.RunDiscarded}, reached: true, expected: true},
+		{name: "errored", phase: RunPhasePlanned, run: &tfe.Run{Status: tfe.RunErrored}, reached: true, expected: true},
+		{name: "unknown-status", phase: RunPhaseApplied, run: &tfe.Run{Status: tfe.RunStatus("plan_reticulating")}, reached: true, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reached, err := tc.phase.reached(tc.run)
+			if reached != tc.reached {
+				t.Errorf("expected reached %t, but received: %t", tc.reached, reached)
+			}
+			if (err != nil) != tc.expected {
+				t.Errorf("expected error %t, but received: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestUntilReached_DefaultPhase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		run      *tfe.Run
+		expected RunPhase
+	}{
+		{name: "plan-only", run: &tfe.Run{PlanOnly: true, AutoApply: true}, expected: RunPhaseCompleted},
+		{name: "auto-apply", run: &tfe.Run{AutoApply: true}, expected: RunPhaseApplied},
+		{name: "policy-checks", run: &tfe.Run{CostEstimate: &tfe.CostEstimate{ID: "ce-1"}, PolicyChecks: []*tfe.PolicyCheck{{ID: "polchk-1"}}}, expected: RunPhasePolicyChecked},
+		{name: "cost-estimation", run: &tfe.Run{CostEstimate: &tfe.CostEstimate{ID: "ce-1"}}, expected: RunPhaseCostEstimated},
+		{name: "confirmable", run: &tfe.Run{}, expected: RunPhaseConfirmable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := defaultRunPhase(tc.run); actual != tc.expected {
+				t.Errorf("expected %q, but received: %q", tc.expected, actual)
+			}
+		})
+	}
+
+	// the default phase uses the same run lifecycle as explicit phases
+	complete := untilReached(&tfe.Run{Status: tfe.RunPending}, "")
+	if done, err := complete(&tfe.Run{Status: tfe.RunStatus("plan_reticulating")}); !done || err == nil {
+		t.Errorf("expected unknown status to fail, but received: %t, %v", done, err)
+	}
+	if done, err := complete(&tfe.Run{Status: tfe.RunPostPlanAwaitingDecision}); !done || err == nil {
+		t.Errorf("expected awaiting decision to fail, but received: %t, %v", done, err)
+	}
+	if done, err := complete(&tfe.Run{Status: tfe.RunCostEstimated, Actions: &tfe.RunActions{IsConfirmable: true}}); !done || err != nil {
+		t.Errorf("expected confirmable run to complete, but received: %t, %v", done, err)
+	}
+
+	// policies awaiting an override stop the default phase without error, unlike explicit later phases
+	for _, tc := range []struct {
+		run    *tfe.Run
+		status tfe.RunStatus
+	}{
+		{run: &tfe.Run{PlanOnly: true, PolicyChecks: []*tfe.PolicyCheck{{ID: "polchk-1"}}}, status: tfe.RunPolicySoftFailed},
+		{run: &tfe.Run{AutoApply: true, PolicyChecks: []*tfe.PolicyCheck{{ID: "polchk-1"}}}, status: tfe.RunPolicySoftFailed},
+		{run: &tfe.Run{AutoApply: true, PolicyChecks: []*tfe.PolicyCheck{{ID: "polchk-1"}}}, status: tfe.RunPolicyOverride},
+	} {
+		complete := untilReached(tc.run, "")
+		if done, err := complete(&tfe.Run{Status: tc.status}); !done || err != nil {
+			t.Errorf("expected %q to complete plan-only %t run, but received: %t, %v", tc.status, tc.run.PlanOnly, done, err)
+		}
+	}
+	complete = untilReached(&tfe.Run{PlanOnly: true}, RunPhaseApplied)
+	if done, err := complete(&tfe.Run{Status: tfe.RunPolicySoftFailed}); !done || err == nil {
+		t.Errorf("expected soft-failed policies to fail the applied phase, but received: %t, %v", done, err)
+	}
+}
+
+func TestParseRunPhase(t *testing.T) {
+	if phase, err := ParseRunPhase(""); err != nil || phase != RunPhaseCompleted {
+		t.Errorf("expected default phase completed, but received: %q, %v", phase, err)
+	}
+	if phase, err := ParseRunPhase("post_plan_completed"); err != nil || phase != RunPhasePostPlanCompleted {
+		t.Errorf("expected phase post_plan_completed, but received: %q, %v", phase, err)
+	}
+	if _, err := ParseRunPhase("finished"); err == nil {
+		t.Error("expected error for unsupported phase")
+	}
+}
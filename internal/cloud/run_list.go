@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+const (
+	listRunsPageSize = 100
+	// bounds the runs scanned for filters applied to listed runs, eg. message and commit
+	listRunsMaxPages = 10
+	// length of abbreviated commit SHAs in run messages
+	shortSHALength = 7
+)
+
+type ListRunsOptions struct {
+	Organization string
+	Workspace    string
+	// filtered by the Runs API
+	Statuses   []string
+	Operations []string
+	Sources    []string
+	// matches the commit of VCS-triggered runs or the run message of API-driven runs, eg. "for SHA (abc1234)"
+	Commit string
+	// case-insensitive message substring
+	Message       string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// maximum number of runs returned
+	Limit int
+}
+
+// RunOperation returns the operation of the run, as accepted by the operation filter of the Runs API
+func RunOperation(run *tfe.Run) tfe.RunOperation {
+	switch {
+	case run.RefreshOnly:
+		return tfe.RunOperationRefreshOnly
+	case run.IsDestroy:
+		return tfe.RunOperationDestroy
+	case run.PlanOnly:
+		return tfe.RunOperationPlanOnly
+	case run.SavePlan:
+		return tfe.RunOperationSavePlan
+	default:
+		return tfe.RunOperationPlanApply
+	}
+}
+
+// RunCommit returns the commit of a VCS-triggered run, empty for runs without ingress attributes
+func RunCommit(run *tfe.Run) string {
+	if run.ConfigurationVersion == nil || run.ConfigurationVersion.IngressAttributes == nil {
+		return ""
+	}
+	return run.ConfigurationVersion.IngressAttributes.CommitSHA
+}
+
+// lists runs of the workspace newest first, paginating until the limit is reached or runs were created before the window
+func (service *runService) ListRuns(ctx context.Context, options ListRunsOptions) ([]*tfe.Run, error) {
+	w, err := service.tfe.Workspaces.Read(ctx, options.Organization, options.Workspace)
+	if err != nil {
+		log.Printf("[ERROR] error reading workspace: %q organization: %q error: %s", options.Workspace, options.Organization, err)
+		return nil, err
+	}
+
+	listOpts := &tfe.RunListOptions{
+		ListOptions: tfe.ListOptions{PageSize: listRunsPageSize},
+		Status:      strings.Join(options.Statuses, ","),
+		Operation:   strings.Join(options.Operations, ","),
+		Source:      strings.Join(options.Sources, ","),
+		Include:     []tfe.RunIncludeOpt{tfe.RunConfigVerIngress},
+	}
+
+	runs := []*tfe.Run{}
+	for page := 1; page <= listRunsMaxPages; page++ {
+		listOpts.PageNumber = page
+		list, err := service.tfe.Runs.List(ctx, w.ID, listOpts)
+		if err != nil {
+			log.Printf("[ERROR] error listing runs of workspace: %q error: %s", options.Workspace, err)
+			return nil, err
+		}
+
+		for _, run := range list.Items {
+			// runs are listed newest first, older runs are outside of the window
+			if !options.CreatedAfter.IsZero() && run.CreatedAt.Before(options.CreatedAfter) {
+				return runs, nil
+			}
+			if !options.matches(run) {
+				continue
+			}
+			runs = append(runs, run)
+			if options.Limit > 0 && len(runs) >= options.Limit {
+				return runs, nil
+			}
+		}
+
+		if list.Pagination == nil || list.NextPage == 0 {
+			return runs, nil
+		}
+	}
+	log.Printf("[DEBUG] stopped listing runs after %d pages", listRunsMaxPages)
+	service.writer.Error(fmt.Sprintf("Warning: only the latest %d runs of workspace %q were checked, older runs are not included", listRunsMaxPages*listRunsPageSize, options.Workspace))
+	return runs, nil
+}
+
+// filters not supported by the Runs API
+func (options ListRunsOptions) matches(run *tfe.Run) bool {
+	if !options.CreatedBefore.IsZero() && !run.CreatedAt.Before(options.CreatedBefore) {
+		return false
+	}
+	if options.Message != "" && !strings.Contains(strings.ToLower(run.Message), strings.ToLower(options.Message)) {
+		return false
+	}
+	if options.Commit != "" {
+		commit := strings.ToLower(options.Commit)
+		short := commit
+		if len(short) > shortSHALength {
+			short = short[:shortSHALength]
+		}
+		runCommit := strings.ToLower(RunCommit(run))
+		if !(runCommit != "" && strings.HasPrefix(runCommit, commit)) && !strings.Contains(strings.ToLower(run.Message), short) {
+			return false
+		}
+	}
+	return true
+}
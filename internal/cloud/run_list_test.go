@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunService_ListRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	workspacesMock := mocks.NewMockWorkspaces(ctrl)
+	workspacesMock.EXPECT().Read(ctx, "org", "ws").Return(&tfe.Workspace{ID: "ws-1"}, nil)
+
+	runsMock := mocks.NewMockRuns(ctrl)
+	expectedOpts := &tfe.RunListOptions{
+		ListOptions: tfe.ListOptions{PageSize: listRunsPageSize, PageNumber: 1},
+		Status:      "planned_and_finished,applied",
+		Operation:   "plan_only",
+		Include:     []tfe.RunIncludeOpt{tfe.RunConfigVerIngress},
+	}
+	runsMock.EXPECT().List(ctx, "ws-1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts *tfe.RunListOptions) (*tfe.RunList, error) {
+		if opts.PageNumber == 1 {
+			if opts.Status != expectedOpts.Status || opts.Operation != expectedOpts.Operation || opts.PageSize != expectedOpts.PageSize {
+				t.Errorf("unexpected list options: %+v", opts)
+			}
+			return &tfe.RunList{
+				Pagination: &tfe.Pagination{CurrentPage: 1, NextPage: 2},
+				Items: []*tfe.Run{
+					{ID: "run-5", Message: "Triggered from HCP Terraform CI by Author (a) for SHA (abc1234)", CreatedAt: now.Add(-1 * time.Hour)},
+					{ID: "run-4", Message: "other commit (fff0000)", CreatedAt: now.Add(-2 * time.Hour)},
+				},
+			}, nil
+		}
+		return &tfe.RunList{
+			Pagination: &tfe.Pagination{CurrentPage: 2},
+			Items: []*tfe.Run{
+				{ID: "run-3", ConfigurationVersion: &tfe.ConfigurationVersion{IngressAttributes: &tfe.IngressAttributes{CommitSHA: "abc1234def"}}, CreatedAt: now.Add(-3 * time.Hour)},
+				{ID: "run-2", Message: "abc1234", CreatedAt: now.Add(-48 * time.Hour)},
+			},
+		}, nil
+	}).Times(2)
+
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Workspaces: workspacesMock, Runs: runsMock}, writer: &defaultWriter{}})
+	runs, err := service.ListRuns(ctx, ListRunsOptions{
+		Organization: "org",
+		Workspace:    "ws",
+		Statuses:     []string{"planned_and_finished", "applied"},
+		Operations:   []string{"plan_only"},
+		Commit:       "abc1234def",
+		CreatedAfter: now.Add(-24 * time.Hour),
+		Limit:        10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ids := []string{}
+	for _, run := range runs {
+		ids = append(ids, run.ID)
+	}
+	// run-4 is another commit and run-2 was created before the window
+	if len(ids) != 2 || ids[0] != "run-5" || ids[1] != "run-3" {
+		t.Errorf("unexpected runs: %v", ids)
+	}
+}
+
+func TestRunService_ListRuns_MaxPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	workspacesMock := mocks.NewMockWorkspaces(ctrl)
+	workspacesMock.EXPECT().Read(ctx, "org", "ws").Return(&tfe.Workspace{ID: "ws-1"}, nil)
+
+	runsMock := mocks.NewMockRuns(ctrl)
+	runsMock.EXPECT().List(ctx, "ws-1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts *tfe.RunListOptions) (*tfe.RunList, error) {
+		return &tfe.RunList{
+			Pagination: &tfe.Pagination{CurrentPage: opts.PageNumber, NextPage: opts.PageNumber + 1},
+			Items:      []*tfe.Run{{ID: fmt.Sprintf("run-%d", opts.PageNumber), Message: "other commit (fff0000)"}},
+		}, nil
+	}).Times(listRunsMaxPages)
+
+	writer := &recordingWriter{}
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Workspaces: workspacesMock, Runs: runsMock}, writer: writer})
+	runs, err := service.ListRuns(ctx, ListRunsOptions{Organization: "org", Workspace: "ws", Commit: "abc1234"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("expected no matching runs, but received: %d", len(runs))
+	}
+	if len(writer.errors) != 1 || !strings.Contains(writer.errors[0], "only the latest 1000 runs") {
+		t.Errorf("expected a warning for the truncated list, but received: %v", writer.errors)
+	}
+}
+
+func TestListRunsOptions_Matches(t *testing.T) {
+	now := time.Now()
+	run := &tfe.Run{Message: "Deploy Networking", CreatedAt: now.Add(-time.Hour)}
+
+	testCases := []struct {
+		name     string
+		options  ListRunsOptions
+		expected bool
+	}{
+		{name: "no-filters", options: ListRunsOptions{}, expected: true},
+		{name: "message", options: ListRunsOptions{Message: "networking"}, expected: true},
+		{name: "other-message", options: ListRunsOptions{Message: "database"}, expected: false},
+		{name: "created-before", options: ListRunsOptions{CreatedBefore: now}, expected: true},
+		{name: "created-after-before", options: ListRunsOptions{CreatedBefore: now.Add(-2 * time.Hour)}, expected: false},
+		{name: "commit-without-match", options: ListRunsOptions{Commit: "abc1234"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.options.matches(run); actual != tc.expected {
+				t.Errorf("expected %t, but received: %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunOperation(t *testing.T) {
+	testCases := []struct {
+		run      *tfe.Run
+		expected tfe.RunOperation
+	}{
+		{run: &tfe.Run{}, expected: tfe.RunOperationPlanApply},
+		{run: &tfe.Run{PlanOnly: true}, expected: tfe.RunOperationPlanOnly},
+		{run: &tfe.Run{IsDestroy: true}, expected: tfe.RunOperationDestroy},
+		{run: &tfe.Run{RefreshOnly: true}, expected: tfe.RunOperationRefreshOnly},
+		{run: &tfe.Run{SavePlan: true}, expected: tfe.RunOperationSavePlan},
+	}
+
+	for _, tc := range testCases {
+		if actual := RunOperation(tc.run); actual != tc.expected {
+			t.Errorf("expected %q for %+v, but received: %q", tc.expected, tc.run, actual)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+type SupersedeRunsOptions struct {
+	Organization string
+	Workspace    string
+	// identifies runs of the same branch or pull request in run messages
+	Tag string
+	// the new run, only runs created before it are superseded
+	RunID     string
+	CreatedAt time.Time
+	Comment   string
+}
+
+// statuses of runs that did not start applying, they may be queued, planning, awaiting a decision or confirmation
+func supersedableStatuses() []string {
+	statuses := []string{}
+	for status, lifecycle := range runLifecycle {
+		pending := lifecycle.state == runStateActive || lifecycle.state == runStateAwaitingDecision
+		if pending && lifecycle.phase.order() < RunPhaseConfirmable.order() {
+			statuses = append(statuses, string(status))
+		}
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// discards or cancels older runs with the tag that did not start applying, returns the superseded runs
+func (service *runService) SupersedeRuns(ctx context.Context, options SupersedeRunsOptions) ([]*tfe.Run, error) {
+	runs, err := service.ListRuns(ctx, ListRunsOptions{
+		Organization:  options.Organization,
+		Workspace:     options.Workspace,
+		Statuses:      supersedableStatuses(),
+		Message:       options.Tag,
+		CreatedBefore: options.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	superseded := []*tfe.Run{}
+	var errs []error
+	for _, run := range runs {
+		if run.ID == options.RunID {
+			continue
+		}
+
+		var supersedeErr error
+		switch {
+		case run.Actions != nil && run.Actions.IsDiscardable:
+			service.writer.Output(fmt.Sprintf("Discarding superseded run: %q", run.ID))
+			_, supersedeErr = service.DiscardRun(ctx, DiscardRunOptions{RunID: run.ID, Comment: options.Comment})
+		case run.Actions != nil && run.Actions.IsCancelable:
+			service.writer.Output(fmt.Sprintf("Canceling superseded run: %q", run.ID))
+			_, supersedeErr = service.CancelRun(ctx, CancelRunOptions{RunID: run.ID, Comment: options.Comment})
+		default:
+			log.Printf("[DEBUG] run: %q with status: %q can neither be discarded nor canceled", run.ID, run.Status)
+			continue
+		}
+
+		if supersedeErr != nil {
+			errs = append(errs, fmt.Errorf("run %s: %w", run.ID, supersedeErr))
+			continue
+		}
+		superseded = append(superseded, run)
+	}
+	return superseded, errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunService_SupersedeRuns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tag := "[tfci:github.com/org/repo#7]"
+	comment := "Superseded by run run-new"
+
+	workspacesMock := mocks.NewMockWorkspaces(ctrl)
+	workspacesMock.EXPECT().Read(ctx, "org", "ws").Return(&tfe.Workspace{ID: "ws-1"}, nil)
+
+	runsMock := mocks.NewMockRuns(ctrl)
+	runsMock.EXPECT().List(ctx, "ws-1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts *tfe.RunListOptions) (*tfe.RunList, error) {
+		if !strings.Contains(opts.Status, "pending") || !strings.Contains(opts.Status, "policy_soft_failed") || strings.Contains(opts.Status, "applying") {
+			t.Errorf("unexpected status filter: %q", opts.Status)
+		}
+		return &tfe.RunList{
+			Pagination: &tfe.Pagination{CurrentPage: 1},
+			Items: []*tfe.Run{
+				// created after the new run, by a newer pipeline
+				{ID: "run-newer", Message: "Deploy " + tag, CreatedAt: created.Add(time.Minute), Actions: &tfe.RunActions{IsDiscardable: true}},
+				{ID: "run-new", Message: "Deploy " + tag, CreatedAt: created, Actions: &tfe.RunActions{IsDiscardable: true}},
+				{ID: "run-planned", Message: "Deploy " + tag, CreatedAt: created.Add(-time.Minute), Actions: &tfe.RunActions{IsDiscardable: true}},
+				{ID: "run-other-pr", Message: "Deploy [tfci:github.com/org/repo#8]", CreatedAt: created.Add(-2 * time.Minute), Actions: &tfe.RunActions{IsDiscardable: true}},
+				// awaits an override of soft-failed policies
+				{ID: "run-soft-failed", Status: tfe.RunPolicySoftFailed, Message: "Deploy " + tag, CreatedAt: created.Add(-150 * time.Second), Actions: &tfe.RunActions{IsDiscardable: true}},
+				{ID: "run-planning", Message: "Deploy " + tag, CreatedAt: created.Add(-3 * time.Minute), Actions: &tfe.RunActions{IsCancelable: true}},
+				{ID: "run-locked", Message: "Deploy " + tag, CreatedAt: created.Add(-4 * time.Minute), Actions: &tfe.RunActions{}},
+			},
+		}, nil
+	})
+	runsMock.EXPECT().Discard(ctx, "run-planned", tfe.RunDiscardOptions{Comment: tfe.String(comment)}).Return(nil)
+	runsMock.EXPECT().ReadWithOptions(gomock.Any(), "run-planned", gomock.Any()).Return(&tfe.Run{ID: "run-planned", Status: tfe.RunDiscarded}, nil)
+	runsMock.EXPECT().Discard(ctx, "run-soft-failed", tfe.RunDiscardOptions{Comment: tfe.String(comment)}).Return(nil)
+	runsMock.EXPECT().ReadWithOptions(gomock.Any(), "run-soft-failed", gomock.Any()).Return(&tfe.Run{ID: "run-soft-failed", Status: tfe.RunDiscarded}, nil)
+	runsMock.EXPECT().Cancel(ctx, "run-planning", tfe.RunCancelOptions{Comment: tfe.String(comment)}).Return(nil)
+	runsMock.EXPECT().ReadWithOptions(gomock.Any(), "run-planning", gomock.Any()).Return(&tfe.Run{ID: "run-planning", Status: tfe.RunCanceled}, nil)
+
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Workspaces: workspacesMock, Runs: runsMock}, writer: &defaultWriter{}})
+	superseded, err := service.SupersedeRuns(ctx, SupersedeRunsOptions{
+		Organization: "org",
+		Workspace:    "ws",
+		Tag:          tag,
+		RunID:        "run-new",
+		CreatedAt:    created,
+		Comment:      comment,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(superseded) != 3 || superseded[0].ID != "run-planned" || superseded[1].ID != "run-soft-failed" || superseded[2].ID != "run-planning" {
+		t.Errorf("unexpected superseded runs: %+v", superseded)
+	}
+}
+
+func TestSupersedableStatuses(t *testing.T) {
+	statuses := strings.Join(supersedableStatuses(), ",") + ","
+	for _, status := range []tfe.RunStatus{tfe.RunPending, tfe.RunPlanning, tfe.RunPlanned, tfe.RunCostEstimated, tfe.RunPolicyOverride, tfe.RunPolicySoftFailed, tfe.RunPostPlanAwaitingDecision} {
+		if !strings.Contains(statuses, string(status)+",") {
+			t.Errorf("expected %q to be superseded", status)
+		}
+	}
+	for _, status := range []tfe.RunStatus{tfe.RunConfirmed, tfe.RunApplying, tfe.RunApplied, tfe.RunPlannedAndSaved, tfe.RunDiscarded} {
+		if strings.Contains(statuses, string(status)+",") {
+			t.Errorf("expected %q not to be superseded", status)
+		}
+	}
+}
+
+func TestRunStatusMessage(t *testing.T) {
+	if actual := runStatusMessage(&tfe.Run{Status: tfe.RunPending, PositionInQueue: 2}); actual != `Run Status: "pending", position in queue: 2` {
+		t.Errorf("expected queue position, but received: %q", actual)
+	}
+	if actual := runStatusMessage(&tfe.Run{Status: tfe.RunPlanning, PositionInQueue: 2}); actual != `Run Status: "planning"` {
+		t.Errorf("expected status only, but received: %q", actual)
+	}
+}
@@ -220,6 +220,9 @@ func TestRunService_CreateRun(t *testing.T) {
 				Include: []tfe.RunIncludeOpt{
 					"cost_estimate",
 					"plan",
+					"apply",
+					"workspace",
+					"configuration_version",
 				},
 			}
 
@@ -270,3 +273,49 @@ func TestRunService_CreateRun(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusChangeNotifier(t *testing.T) {
+	observed := []tfe.RunStatus{}
+	notify := statusChangeNotifier(func(run *tfe.Run) {
+		observed = append(observed, run.Status)
+	})
+
+	for _, status := range []tfe.RunStatus{
+		tfe.RunPending,
+		tfe.RunPlanning,
+		tfe.RunPlanning,
+		tfe.RunPlanned,
+		tfe.RunPlanned,
+		tfe.RunApplying,
+	} {
+		notify(&tfe.Run{Status: status})
+	}
+	notify(nil)
+
+	expected := []tfe.RunStatus{tfe.RunPending, tfe.RunPlanning, tfe.RunPlanned, tfe.RunApplying}
+	if len(observed) != len(expected) {
+		t.Fatalf("expected %v, but received: %v", expected, observed)
+	}
+	for i := range expected {
+		if observed[i] != expected[i] {
+			t.Errorf("expected %v, but received: %v", expected, observed)
+		}
+	}
+
+	// nil callback is a no-op
+	statusChangeNotifier(nil)(&tfe.Run{Status: tfe.RunPending})
+}
+
+func TestRunService_CommentRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	commentsMock := mocks.NewMockComments(ctrl)
+	commentsMock.EXPECT().Create(ctx, "run-123", tfe.CommentCreateOptions{Body: "approved"}).Return(&tfe.Comment{ID: "wsc-1", Body: "approved"}, nil)
+
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Comments: commentsMock}, writer: &defaultWriter{}})
+	if err := service.CommentRun(ctx, CommentRunOptions{RunID: "run-123", Body: "approved"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
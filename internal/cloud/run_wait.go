@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/sethvargo/go-retry"
+)
+
+// reports whether polling a run is done, runs that ended without completing are returned as error
+type runCompleteFunc func(run *tfe.Run) (bool, error)
+
+type WaitRunOptions struct {
+	RunID string
+	// phase the run is waited for, defaults to the phase run create waits for
+	Until          RunPhase
+	OnStatusChange RunStatusChangeFunc
+}
+
+// waits for a run created without waiting, stopping at the same phase of the run lifecycle as run create
+func (service *runService) WaitRun(ctx context.Context, options WaitRunOptions) (*tfe.Run, error) {
+	run, err := service.GetRun(ctx, GetRunOptions{
+		RunID: options.RunID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	complete := untilReached(run, options.Until)
+
+	notifyStatusChange := statusChangeNotifier(options.OnStatusChange)
+	notifyStatusChange(run)
+
+	return service.pollRun(ctx, run, complete, notifyStatusChange, "wait run")
+}
+
+// polling stops at the phase, or at the phase run create stops at by default when no phase is set
+func untilReached(run *tfe.Run, phase RunPhase) runCompleteFunc {
+	if phase != "" {
+		return phase.reached
+	}
+	phase = defaultRunPhase(run)
+	log.Printf("[DEBUG] PlanOnly: %t, AutoApply: %t, CostEstimation: %t, PolicyChecks: %t, waiting until phase: %s", run.PlanOnly, run.AutoApply, hasCostEstimate(run), hasPolicyChecks(run), phase)
+	return func(r *tfe.Run) (bool, error) {
+		// soft-failed and overridable policies end run create successfully, speculative plans cannot be overridden
+		if r.Status == tfe.RunPolicySoftFailed || r.Status == tfe.RunPolicyOverride {
+			return RunPhasePolicyChecked.reached(r)
+		}
+		return phase.reached(r)
+	}
+}
+
+// reads the run until it is complete, the last read run is returned with errors
+func (service *runService) pollRun(ctx context.Context, run *tfe.Run, complete runCompleteFunc, notifyStatusChange RunStatusChangeFunc, action string) (*tfe.Run, error) {
+	retryErr := retry.Do(ctx, defaultBackoff(), func(ctx context.Context) error {
+		log.Printf("[DEBUG] Monitoring run status...")
+		r, err := service.GetRun(ctx, GetRunOptions{
+			RunID: run.ID,
+		})
+
+		// update run
+		run = r
+
+		if err != nil {
+			return err
+		}
+
+		service.writer.Output(runStatusMessage(run))
+		notifyStatusChange(run)
+
+		done, err := complete(r)
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+		return retryableTimeoutError(action)
+	})
+
+	if retryErr != nil {
+		return run, retryErr
+	}
+
+	return run, nil
+}
+
+// formats the polled run status, including the queue position of pending runs
+func runStatusMessage(run *tfe.Run) string {
+	if run.Status == tfe.RunPending && run.PositionInQueue > 0 {
+		return fmt.Sprintf("Run Status: %q, position in queue: %d", run.Status, run.PositionInQueue)
+	}
+	return fmt.Sprintf("Run Status: %q", run.Status)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunService_CreateRun_NoWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tc := createRunTestCase{
+		orgName:          "test",
+		workspaceName:    "my-workspace",
+		ctx:              context.Background(),
+		tfeWorkspace:     &tfe.Workspace{ID: "ws-***"},
+		tfeConfigVersion: &tfe.ConfigurationVersion{ID: "cv-***", Status: tfe.ConfigurationUploaded},
+		tfeRun:           &tfe.Run{ID: "run-***", Status: tfe.RunPending},
+	}
+	// the created run is not read again
+	workspaceMock, configVersionMock, runsMock := testGenerateServiceMocks(t, ctrl, tc)
+
+	service := NewRunService(&cloudMeta{
+		tfe:    &tfe.Client{Workspaces: workspaceMock, ConfigurationVersions: configVersionMock, Runs: runsMock},
+		writer: &defaultWriter{},
+	})
+	run, err := service.CreateRun(tc.ctx, CreateRunOptions{
+		Organization:           tc.orgName,
+		ConfigurationVersionID: tc.tfeConfigVersion.ID,
+		Workspace:              tc.workspaceName,
+		RunVariables:           []*tfe.RunVariable{},
+		NoWait:                 true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if run.ID != "run-***" || run.Status != tfe.RunPending {
+		t.Errorf("expected the created run, but received: %+v", run)
+	}
+}
+
+func TestRunService_WaitRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		run      *tfe.Run
+		until    RunPhase
+		statuses []tfe.RunStatus
+		expected bool
+	}{
+		{
+			name:     "plan-only-desired-status",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPending, PlanOnly: true},
+			statuses: []tfe.RunStatus{tfe.RunPlanning, tfe.RunPlannedAndFinished},
+		},
+		{
+			name:     "auto-apply-desired-status",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, AutoApply: true},
+			statuses: []tfe.RunStatus{tfe.RunApplying, tfe.RunApplied},
+		},
+		{
+			name:     "plan-only-policy-soft-failed",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, PlanOnly: true},
+			statuses: []tfe.RunStatus{tfe.RunPolicySoftFailed},
+		},
+		{
+			name:     "auto-apply-policy-soft-failed",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, AutoApply: true},
+			statuses: []tfe.RunStatus{tfe.RunPolicySoftFailed},
+		},
+		{
+			name:     "auto-apply-policy-override",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, AutoApply: true},
+			statuses: []tfe.RunStatus{tfe.RunPolicyOverride},
+		},
+		{
+			name:     "until-applied-policy-override",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, AutoApply: true},
+			until:    RunPhaseApplied,
+			statuses: []tfe.RunStatus{tfe.RunPolicyOverride},
+			expected: true,
+		},
+		{
+			name:     "until-planned",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, AutoApply: true},
+			until:    RunPhasePlanned,
+			statuses: []tfe.RunStatus{tfe.RunApplying},
+		},
+		{
+			name:     "noop-status",
+			run:      &tfe.Run{ID: "run-123", Status: tfe.RunPlanning, PlanOnly: true},
+			statuses: []tfe.RunStatus{tfe.RunDiscarded},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			runsMock := mocks.NewMockRuns(ctrl)
+			calls := []any{runsMock.EXPECT().ReadWithOptions(ctx, "run-123", gomock.Any()).Return(tc.run, nil)}
+			for _, status := range tc.statuses {
+				calls = append(calls, runsMock.EXPECT().ReadWithOptions(ctx, "run-123", gomock.Any()).Return(&tfe.Run{
+					ID:     "run-123",
+					Status: status,
+				}, nil))
+			}
+			gomock.InOrder(calls...)
+
+			observed := []tfe.RunStatus{}
+			service := NewRunService(&cloudMeta{tfe: &tfe.Client{Runs: runsMock}, writer: &defaultWriter{}})
+			run, err := service.WaitRun(ctx, WaitRunOptions{
+				RunID: "run-123",
+				Until: tc.until,
+				OnStatusChange: func(r *tfe.Run) {
+					observed = append(observed, r.Status)
+				},
+			})
+			if (err != nil) != tc.expected {
+				t.Fatalf("expected error %t, but received: %v", tc.expected, err)
+			}
+			last := tc.statuses[len(tc.statuses)-1]
+			if run.Status != last || observed[len(observed)-1] != last {
+				t.Errorf("expected run with status %q, but received: %+v, status changes: %v", last, run, observed)
+			}
+		})
+	}
+}
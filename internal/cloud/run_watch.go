@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+type WatchRunOptions struct {
+	RunID          string
+	Until          RunPhase
+	OnStatusChange RunStatusChangeFunc
+}
+
+// follows an existing run until it reaches the phase, the run may have been started by any source
+func (service *runService) WatchRun(ctx context.Context, options WatchRunOptions) (*tfe.Run, error) {
+	run, err := service.GetRun(ctx, GetRunOptions{
+		RunID: options.RunID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyStatusChange := statusChangeNotifier(options.OnStatusChange)
+	notifyStatusChange(run)
+
+	return service.pollRun(ctx, run, options.Until.reached, notifyStatusChange, "watch run")
+}
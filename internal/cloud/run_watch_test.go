@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/go-tfe/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRunService_WatchRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	runsMock := mocks.NewMockRuns(ctrl)
+	runsMock.EXPECT().ReadWithOptions(gomock.Any(), "run-123", gomock.Any()).Return(&tfe.Run{
+		ID:     "run-123",
+		Status: tfe.RunApplied,
+	}, nil).Times(2)
+
+	statuses := []tfe.RunStatus{}
+	service := NewRunService(&cloudMeta{tfe: &tfe.Client{Runs: runsMock}, writer: &defaultWriter{}})
+	run, err := service.WatchRun(ctx, WatchRunOptions{
+		RunID: "run-123",
+		Until: RunPhaseCompleted,
+		OnStatusChange: func(r *tfe.Run) {
+			statuses = append(statuses, r.Status)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if run.Status != tfe.RunApplied || len(statuses) != 1 || statuses[0] != tfe.RunApplied {
+		t.Errorf("unexpected run %+v or status changes %v", run, statuses)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/plancheck"
+)
+
+// applyGuard limits the blast radius of applies, checked against the plan of a run before it is confirmed
+type applyGuard struct {
+	// limits are disabled when negative
+	maxDestroy int
+	maxChanges int
+	// resource type patterns that must not be replaced
+	denyReplace []string
+}
+
+func (g *applyGuard) enabled() bool {
+	return g.maxDestroy >= 0 || g.maxChanges >= 0 || len(g.denyReplace) > 0
+}
+
+// returns the safeguards violated by the plan of the run. Replacements are resolved from the
+// JSON execution plan, which is only read when -deny-replace is set.
+func (c *Meta) applyGuardViolations(guard *applyGuard, run *tfe.Run) ([]*plancheck.Violation, error) {
+	violations := []*plancheck.Violation{}
+	if !guard.enabled() {
+		return violations, nil
+	}
+	plan := run.Plan
+	if plan == nil {
+		return nil, fmt.Errorf("run %s has no plan to check safeguards against", run.ID)
+	}
+
+	if guard.maxDestroy >= 0 && plan.ResourceDestructions > guard.maxDestroy {
+		violations = append(violations, &plancheck.Violation{
+			Rule:    "max-destroy",
+			Message: fmt.Sprintf("%d resource(s) to destroy exceed the maximum of %d", plan.ResourceDestructions, guard.maxDestroy),
+		})
+	}
+	changes := plan.ResourceAdditions + plan.ResourceChanges + plan.ResourceDestructions + plan.ResourceImports
+	if guard.maxChanges >= 0 && changes > guard.maxChanges {
+		violations = append(violations, &plancheck.Violation{
+			Rule:    "max-changes",
+			Message: fmt.Sprintf("%d resource change(s) exceed the maximum of %d", changes, guard.maxChanges),
+		})
+	}
+
+	if len(guard.denyReplace) > 0 {
+		data, err := c.cloud.GetPlanJSON(c.appCtx, cloud.GetPlanJSONOptions{PlanID: plan.ID})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read JSON execution plan for -deny-replace: %w", err)
+		}
+		resourceChanges, err := cloud.ParsePlanChanges(data)
+		if err != nil {
+			return nil, err
+		}
+		rules := &plancheck.Rules{Rules: []*plancheck.Rule{{
+			Name:          "deny-replace",
+			Actions:       []string{cloud.ChangeReplace},
+			ResourceTypes: guard.denyReplace,
+			Deny:          true,
+		}}}
+		violations = append(violations, rules.Evaluate(resourceChanges, nil)...)
+	}
+	return violations, nil
+}
+
+// run comment recording why safeguards were overridden
+func applyOverrideComment(justification string, violations []*plancheck.Violation) string {
+	var b strings.Builder
+	b.WriteString("Apply safeguards overridden with -allow-destructive: ")
+	b.WriteString(justification)
+	b.WriteString("\n")
+	for _, v := range violations {
+		fmt.Fprintf(&b, "\n- [%s] %s", v.Rule, v.Message)
+	}
+	return b.String()
+}
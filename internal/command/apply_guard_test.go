@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/plancheck"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// returns a confirmable run replacing a database and records applies and comments
+type applyRunStub struct {
+	runLinkStub
+	applied  bool
+	comments []string
+}
+
+func (s *applyRunStub) GetRun(_ context.Context, options cloud.GetRunOptions) (*tfe.Run, error) {
+	return &tfe.Run{
+		ID:        options.RunID,
+		Status:    tfe.RunPlanned,
+		Actions:   &tfe.RunActions{IsConfirmable: true},
+		Workspace: &tfe.Workspace{ID: "ws-1"},
+		Plan:      &tfe.Plan{ID: "plan-123", Status: tfe.PlanFinished, ResourceAdditions: 1, ResourceDestructions: 1},
+	}, nil
+}
+
+func (s *applyRunStub) ApplyRun(_ context.Context, options cloud.ApplyRunOptions) (*tfe.Run, error) {
+	s.applied = true
+	return &tfe.Run{
+		ID:        options.RunID,
+		Status:    tfe.RunApplied,
+		Workspace: &tfe.Workspace{ID: "ws-1"},
+		Apply:     &tfe.Apply{ID: "apply-123"},
+	}, nil
+}
+
+func (s *applyRunStub) CommentRun(_ context.Context, options cloud.CommentRunOptions) error {
+	s.comments = append(s.comments, options.Body)
+	return nil
+}
+
+func (s *applyRunStub) LogTaskStage(context.Context, *tfe.Run, tfe.Stage) error {
+	return nil
+}
+
+func (s *applyRunStub) GetApplyLogs(context.Context, string) (*cloud.RunLog, error) {
+	return &cloud.RunLog{}, nil
+}
+
+func TestApplyRunCommand_Safeguards(t *testing.T) {
+	testCases := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		status       Status
+		applied      bool
+		violations   []string
+	}{
+		{
+			name:         "within-limits",
+			args:         []string{"-max-destroy", "1", "-max-changes", "2"},
+			expectedCode: 0,
+			status:       Success,
+			applied:      true,
+		},
+		{
+			name:         "max-destroy",
+			args:         []string{"-max-destroy", "0"},
+			expectedCode: violationExitCode,
+			status:       Blocked,
+			violations:   []string{"max-destroy"},
+		},
+		{
+			name:         "deny-replace",
+			args:         []string{"-deny-replace", "aws_db_*", "-max-changes", "1"},
+			expectedCode: violationExitCode,
+			status:       Blocked,
+			violations:   []string{"max-changes", "deny-replace"},
+		},
+		{
+			name:         "deny-replace-other-type",
+			args:         []string{"-deny-replace", "aws_s3_bucket"},
+			expectedCode: 0,
+			status:       Success,
+			applied:      true,
+		},
+		{
+			name:         "allow-destructive",
+			args:         []string{"-max-destroy", "0", "-allow-destructive", "-justification", "approved in change CHG-1"},
+			expectedCode: 0,
+			status:       Success,
+			applied:      true,
+			violations:   []string{"max-destroy"},
+		},
+		{
+			name:         "allow-destructive-without-justification",
+			args:         []string{"-max-destroy", "0", "-allow-destructive"},
+			expectedCode: 1,
+			status:       Error,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			w := writer.NewWriter(ui)
+			runStub := &applyRunStub{}
+			cloudService := cloud.NewCloud(&tfe.Client{}, w)
+			cloudService.RunService = runStub
+			cloudService.PlanService = &planStub{planJSON: testResourceChangesPlan}
+			meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+			c := &ApplyRunCommand{Meta: meta}
+			args := append([]string{"-run", "run-123", "-stream-logs=false", "-github-check=false"}, tc.args...)
+			if code := c.Run(args); code != tc.expectedCode {
+				t.Fatalf("expected exit code %d, but received: %d, %s", tc.expectedCode, code, ui.ErrorWriter.String())
+			}
+			if v := meta.messages["status"]; v == nil || v.value != string(tc.status) {
+				t.Errorf("expected status %q, but received: %+v", tc.status, v)
+			}
+			if runStub.applied != tc.applied {
+				t.Errorf("expected applied %t, but received: %t", tc.applied, runStub.applied)
+			}
+
+			rules := []string{}
+			if v := meta.messages["violations"]; v != nil {
+				for _, violation := range v.value.([]*plancheck.Violation) {
+					rules = append(rules, violation.Rule)
+				}
+			}
+			if strings.Join(rules, ",") != strings.Join(tc.violations, ",") {
+				t.Errorf("expected violations %v, but received: %v", tc.violations, rules)
+			}
+
+			if tc.status == Blocked && !strings.Contains(ui.ErrorWriter.String(), "-allow-destructive") {
+				t.Errorf("expected explanation how to override safeguards, but received: %s", ui.ErrorWriter.String())
+			}
+			if tc.name == "allow-destructive" {
+				if len(runStub.comments) != 1 || !strings.Contains(runStub.comments[0], "approved in change CHG-1") || !strings.Contains(runStub.comments[0], "[max-destroy]") {
+					t.Errorf("expected justification run comment, but received: %q", runStub.comments)
+				}
+			} else if len(runStub.comments) != 0 {
+				t.Errorf("expected no run comment, but received: %q", runStub.comments)
+			}
+		})
+	}
+}
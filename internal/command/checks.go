@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/github"
+)
+
+// runCheck reports a run as a GitHub check on the triggering commit,
+// a nil *runCheck is valid and ignores all updates
+type runCheck struct {
+	meta   *Meta
+	client *github.Client
+	name   string
+	sha    string
+	// created check, nil until the first update
+	check *github.CheckRun
+}
+
+// maps run status to check status while the run is being polled
+func checkStatus(status tfe.RunStatus) string {
+	switch status {
+	case tfe.RunPending, tfe.RunFetching, tfe.RunQueuing, tfe.RunPlanQueued, tfe.RunApplyQueued, tfe.RunQueuingApply:
+		return github.CheckStatusQueued
+	default:
+		return github.CheckStatusInProgress
+	}
+}
+
+// maps the final run status and polling error to a check conclusion
+func checkConclusion(run *tfe.Run, err error) string {
+	var timeoutErr *cloud.RetryTimeoutError
+	if errors.As(err, &timeoutErr) {
+		return github.CheckConclusionTimedOut
+	}
+	if run != nil {
+		switch run.Status {
+		// policies failed, the run is not successful until they are overridden
+		case tfe.RunPolicySoftFailed, tfe.RunPolicyOverride:
+			return github.CheckConclusionNeutral
+		case tfe.RunCanceled, tfe.RunDiscarded, cloud.ForceCancel:
+			return github.CheckConclusionCancelled
+		}
+	}
+	if err != nil {
+		return github.CheckConclusionFailure
+	}
+	return github.CheckConclusionSuccess
+}
+
+// returns a check for the current commit when enabled and running in GitHub Actions with a token, otherwise nil
+func (c *Meta) newRunCheck(enabled bool, name string) *runCheck {
+	if !enabled || c.env == nil {
+		return nil
+	}
+	ctx, ok := c.env.Context.(*environment.GitHubContext)
+	if !ok {
+		return nil
+	}
+	if ctx.Token() == "" {
+		log.Printf("[DEBUG] GITHUB_TOKEN is not set, skipping check run: %s", name)
+		return nil
+	}
+	client, err := github.NewClient(ctx.APIURL(), ctx.Token(), ctx.Repository())
+	if err != nil {
+		log.Printf("[ERROR] unable to create github client for check run: %s", err.Error())
+		return nil
+	}
+	// pull request workflows run for the merge commit, checks are shown on the head commit of the pull request
+	sha := ctx.SHA()
+	if pr := ctx.PullRequest(); pr != nil && pr.HeadSHA != "" {
+		sha = pr.HeadSHA
+	}
+	return &runCheck{
+		meta:   c,
+		client: client,
+		name:   name,
+		sha:    sha,
+	}
+}
+
+// reports run progress, used as the status change callback of polling loops
+func (rc *runCheck) update(run *tfe.Run) {
+	if rc == nil || run == nil {
+		return
+	}
+	link := rc.meta.runLink(run)
+	rc.send(&github.CheckRun{
+		Status:     checkStatus(run.Status),
+		DetailsURL: link,
+		ExternalID: run.ID,
+		Output: &github.CheckRunOutput{
+			Title:   fmt.Sprintf("Run %s", run.Status),
+			Summary: fmt.Sprintf("HCP Terraform run [%s](%s) is `%s`", run.ID, link, run.Status),
+		},
+	})
+}
+
+// completes the check with a conclusion resolved from the final run status
+func (rc *runCheck) complete(run *tfe.Run, runErr error) {
+	if rc == nil {
+		return
+	}
+	check := &github.CheckRun{
+		Status:     github.CheckStatusCompleted,
+		Conclusion: checkConclusion(run, runErr),
+		Output: &github.CheckRunOutput{
+			Title:   "Run failed",
+			Summary: "HCP Terraform run could not be completed",
+		},
+	}
+	if run != nil {
+		link := rc.meta.runLink(run)
+		check.DetailsURL = link
+		check.ExternalID = run.ID
+		check.Output.Title = fmt.Sprintf("Run %s", run.Status)
+		if run.Plan != nil && run.Plan.Status == tfe.PlanFinished {
+			check.Output.Title = fmt.Sprintf("Run %s: %d to add, %d to change, %d to destroy", run.Status, run.Plan.ResourceAdditions, run.Plan.ResourceChanges, run.Plan.ResourceDestructions)
+		}
+		check.Output.Summary = (&runSummary{
+			title:        rc.name,
+			runID:        run.ID,
+			runLink:      link,
+			runStatus:    string(run.Status),
+			plan:         run.Plan,
+			costEstimate: run.CostEstimate,
+		}).markdown()
+	}
+	if runErr != nil {
+		check.Output.Text = runErr.Error()
+	}
+	rc.send(check)
+}
+
+// creates the check on first use and updates it afterwards, reporting is disabled after the first failure
+func (rc *runCheck) send(check *github.CheckRun) {
+	if rc.client == nil {
+		return
+	}
+
+	var result *github.CheckRun
+	var err error
+	if rc.check == nil {
+		check.Name = rc.name
+		check.HeadSHA = rc.sha
+		result, err = rc.client.CreateCheckRun(rc.meta.appCtx, check)
+	} else {
+		result, err = rc.client.UpdateCheckRun(rc.meta.appCtx, rc.check.ID, check)
+	}
+
+	if err != nil {
+		rc.meta.writer.Error(fmt.Sprintf("unable to report GitHub check %q, the token requires the checks: write permission: %s", rc.name, err.Error()))
+		rc.client = nil
+		return
+	}
+	rc.check = result
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/github"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// resolves run links without calling HCP Terraform, other methods are not implemented
+type runLinkStub struct {
+	cloud.RunService
+}
+
+func (s *runLinkStub) RunLink(_ context.Context, org string, run *tfe.Run) (string, error) {
+	return "https://app.terraform.io/app/" + org + "/workspaces/ws/runs/" + run.ID, nil
+}
+
+func TestCheckConclusion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		run      *tfe.Run
+		err      error
+		expected string
+	}{
+		{name: "planned-and-finished", run: &tfe.Run{Status: tfe.RunPlannedAndFinished}, expected: github.CheckConclusionSuccess},
+		{name: "applied", run: &tfe.Run{Status: tfe.RunApplied}, expected: github.CheckConclusionSuccess},
+		{name: "policy-soft-failed", run: &tfe.Run{Status: tfe.RunPolicySoftFailed}, expected: github.CheckConclusionNeutral},
+		{name: "policy-override", run: &tfe.Run{Status: tfe.RunPolicyOverride}, expected: github.CheckConclusionNeutral},
+		{name: "errored", run: &tfe.Run{Status: tfe.RunErrored}, err: errors.New("run has ended with: 'errored' status"), expected: github.CheckConclusionFailure},
+		{name: "canceled", run: &tfe.Run{Status: tfe.RunCanceled}, err: errors.New("run has ended with: 'canceled' status"), expected: github.CheckConclusionCancelled},
+		{name: "timeout", run: &tfe.Run{Status: tfe.RunPlanning}, err: &cloud.RetryTimeoutError{}, expected: github.CheckConclusionTimedOut},
+		{name: "no-run", err: errors.New("workspace not found"), expected: github.CheckConclusionFailure},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if conclusion := checkConclusion(tc.run, tc.err); conclusion != tc.expected {
+				t.Errorf("expected %q, but received: %q", tc.expected, conclusion)
+			}
+		})
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	var mu sync.Mutex
+	requests := []*github.CheckRun{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		check := &github.CheckRun{}
+		json.NewDecoder(r.Body).Decode(check)
+		requests = append(requests, check)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octocat/hello-world/check-runs":
+			w.WriteHeader(http.StatusCreated)
+			check.ID = 1
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/octocat/hello-world/check-runs/1":
+			check.ID = 1
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		json.NewEncoder(w).Encode(check)
+	}))
+	defer ts.Close()
+
+	client, err := github.NewClient(ts.URL, "gh-token", "octocat/hello-world")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = &runLinkStub{}
+	m := NewMetaOpts(context.Background(), cloudService, &environment.CI{}, WithOrg("org"), WithWriter(w))
+
+	rc := &runCheck{meta: m, client: client, name: "HCP Terraform / ws", sha: "abc123"}
+	rc.update(&tfe.Run{ID: "run-1", Status: tfe.RunPlanQueued})
+	rc.update(&tfe.Run{ID: "run-1", Status: tfe.RunPlanning})
+	rc.complete(&tfe.Run{ID: "run-1", Status: tfe.RunPolicySoftFailed, Plan: &tfe.Plan{Status: tfe.PlanFinished, ResourceAdditions: 1}}, nil)
+
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, but received: %d", len(requests))
+	}
+	if requests[0].Name != "HCP Terraform / ws" || requests[0].HeadSHA != "abc123" || requests[0].Status != github.CheckStatusQueued {
+		t.Errorf("unexpected create request: %+v", requests[0])
+	}
+	if requests[0].DetailsURL != "https://app.terraform.io/app/org/workspaces/ws/runs/run-1" {
+		t.Errorf("unexpected details url: %q", requests[0].DetailsURL)
+	}
+	if requests[1].Status != github.CheckStatusInProgress {
+		t.Errorf("expected in_progress, but received: %+v", requests[1])
+	}
+	if requests[2].Status != github.CheckStatusCompleted || requests[2].Conclusion != github.CheckConclusionNeutral {
+		t.Errorf("expected neutral conclusion, but received: %+v", requests[2])
+	}
+	if requests[2].Output == nil || requests[2].Output.Title != "Run policy_soft_failed: 1 to add, 0 to change, 0 to destroy" {
+		t.Errorf("unexpected output: %+v", requests[2].Output)
+	}
+
+	// nil checks ignore updates
+	var nilCheck *runCheck
+	nilCheck.update(&tfe.Run{ID: "run-1"})
+	nilCheck.complete(nil, nil)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/github"
+)
+
+// runDeployment reports an apply as a GitHub deployment of the triggering commit,
+// a nil *runDeployment is valid and ignores all updates
+type runDeployment struct {
+	meta        *Meta
+	client      *github.Client
+	environment string
+	sha         string
+	// created deployment, nil until the first update
+	deployment *github.Deployment
+	// last reported state, statuses are only sent when it changes
+	state string
+}
+
+// maps run status to a deployment state, empty when the status is not part of the apply
+func deploymentState(status tfe.RunStatus) string {
+	switch status {
+	case tfe.RunConfirmed, tfe.RunApplyQueued, tfe.RunQueuingApply:
+		return github.DeploymentStateQueued
+	case tfe.RunPreApplyRunning, tfe.RunPreApplyCompleted, tfe.RunApplying:
+		return github.DeploymentStateInProgress
+	case tfe.RunApplied:
+		return github.DeploymentStateSuccess
+	case tfe.RunErrored:
+		return github.DeploymentStateFailure
+	case tfe.RunCanceled, tfe.RunDiscarded, cloud.ForceCancel:
+		return github.DeploymentStateError
+	default:
+		return ""
+	}
+}
+
+// returns a deployment to environment when set and running in GitHub Actions with a token, otherwise nil
+func (c *Meta) newRunDeployment(environmentName string) *runDeployment {
+	if environmentName == "" || c.env == nil {
+		return nil
+	}
+	ctx, ok := c.env.Context.(*environment.GitHubContext)
+	if !ok {
+		c.writer.Error("GitHub deployments are only supported in GitHub Actions")
+		return nil
+	}
+	client, err := github.NewClient(ctx.APIURL(), ctx.Token(), ctx.Repository())
+	if err != nil {
+		c.writer.Error(fmt.Sprintf("unable to create GitHub deployment: %s", err.Error()))
+		return nil
+	}
+	return &runDeployment{
+		meta:        c,
+		client:      client,
+		environment: environmentName,
+		sha:         ctx.SHA(),
+	}
+}
+
+// reports apply progress, used as the status change callback of polling loops
+func (rd *runDeployment) update(run *tfe.Run) {
+	if rd == nil || run == nil {
+		return
+	}
+	state := deploymentState(run.Status)
+	if state == "" || state == rd.state {
+		return
+	}
+	rd.send(run, state)
+}
+
+// reports the final deployment state, failures are reported when the apply did not reach a final status
+func (rd *runDeployment) complete(run *tfe.Run, runErr error) {
+	if rd == nil {
+		return
+	}
+	state := github.DeploymentStateSuccess
+	if run != nil {
+		state = deploymentState(run.Status)
+	}
+	if runErr != nil && state != github.DeploymentStateFailure {
+		state = github.DeploymentStateError
+	}
+	if state == "" || state == rd.state {
+		return
+	}
+	rd.send(run, state)
+}
+
+// creates the deployment on first use and adds a status, reporting is disabled after the first failure
+func (rd *runDeployment) send(run *tfe.Run, state string) {
+	if rd.client == nil {
+		return
+	}
+
+	if rd.deployment == nil {
+		description := "HCP Terraform apply"
+		if run != nil {
+			description = fmt.Sprintf("HCP Terraform apply %s", run.ID)
+		}
+		deployment, err := rd.client.CreateDeployment(rd.meta.appCtx, &github.Deployment{
+			Ref:         rd.sha,
+			Environment: rd.environment,
+			Description: description,
+		})
+		if err != nil {
+			rd.fail(err)
+			return
+		}
+		log.Printf("[DEBUG] created GitHub deployment: %d for environment: %s", deployment.ID, rd.environment)
+		rd.deployment = deployment
+	}
+
+	status := &github.DeploymentStatus{
+		State:       state,
+		Description: "HCP Terraform apply",
+	}
+	if run != nil {
+		status.LogURL = rd.meta.runLink(run)
+		status.Description = fmt.Sprintf("Run %s", run.Status)
+	}
+	if _, err := rd.client.CreateDeploymentStatus(rd.meta.appCtx, rd.deployment.ID, status); err != nil {
+		rd.fail(err)
+		return
+	}
+	rd.state = state
+}
+
+func (rd *runDeployment) fail(err error) {
+	rd.meta.writer.Error(fmt.Sprintf("unable to report GitHub deployment to %q: %s", rd.environment, err.Error()))
+	rd.client = nil
+}
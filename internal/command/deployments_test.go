@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/github"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// in-memory stand-in for the deployments api, records created deployments and statuses
+type deploymentServer struct {
+	mu          sync.Mutex
+	deployments []*github.Deployment
+	statuses    []*github.DeploymentStatus
+}
+
+func (s *deploymentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/octocat/hello-world/deployments":
+		deployment := &github.Deployment{}
+		json.NewDecoder(r.Body).Decode(deployment)
+		deployment.ID = int64(len(s.deployments) + 1)
+		s.deployments = append(s.deployments, deployment)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(deployment)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/octocat/hello-world/deployments/1/statuses":
+		status := &github.DeploymentStatus{}
+		json.NewDecoder(r.Body).Decode(status)
+		status.ID = int64(len(s.statuses) + 1)
+		s.statuses = append(s.statuses, status)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(status)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}
+}
+
+func TestRunDeployment(t *testing.T) {
+	testCases := []struct {
+		name     string
+		statuses []tfe.RunStatus
+		err      error
+		expected []string
+	}{
+		{
+			name:     "applied",
+			statuses: []tfe.RunStatus{tfe.RunConfirmed, tfe.RunApplyQueued, tfe.RunApplying, tfe.RunApplied},
+			expected: []string{github.DeploymentStateQueued, github.DeploymentStateInProgress, github.DeploymentStateSuccess},
+		},
+		{
+			name:     "errored",
+			statuses: []tfe.RunStatus{tfe.RunApplyQueued, tfe.RunApplying, tfe.RunErrored},
+			err:      errors.New("run has ended with: 'errored' status"),
+			expected: []string{github.DeploymentStateQueued, github.DeploymentStateInProgress, github.DeploymentStateFailure},
+		},
+		{
+			name:     "timeout",
+			statuses: []tfe.RunStatus{tfe.RunApplyQueued},
+			err:      &cloud.RetryTimeoutError{},
+			expected: []string{github.DeploymentStateQueued, github.DeploymentStateError},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &deploymentServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			client, err := github.NewClient(ts.URL, "gh-token", "octocat/hello-world")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ui := cli.NewMockUi()
+			w := writer.NewWriter(ui)
+			cloudService := cloud.NewCloud(&tfe.Client{}, w)
+			cloudService.RunService = &runLinkStub{}
+			m := NewMetaOpts(context.Background(), cloudService, &environment.CI{}, WithOrg("org"), WithWriter(w))
+
+			rd := &runDeployment{meta: m, client: client, environment: "production", sha: "abc123"}
+			var run *tfe.Run
+			for _, status := range tc.statuses {
+				run = &tfe.Run{ID: "run-1", Status: status}
+				rd.update(run)
+			}
+			rd.complete(run, tc.err)
+
+			if len(server.deployments) != 1 {
+				t.Fatalf("expected 1 deployment, but received: %d", len(server.deployments))
+			}
+			if d := server.deployments[0]; d.Ref != "abc123" || d.Environment != "production" || d.RequiredContexts == nil {
+				t.Errorf("unexpected deployment: %+v", d)
+			}
+
+			states := []string{}
+			for _, s := range server.statuses {
+				states = append(states, s.State)
+				if s.LogURL != "https://app.terraform.io/app/org/workspaces/ws/runs/run-1" {
+					t.Errorf("unexpected log url: %q", s.LogURL)
+				}
+			}
+			if len(states) != len(tc.expected) {
+				t.Fatalf("expected states %v, but received: %v", tc.expected, states)
+			}
+			for i := range states {
+				if states[i] != tc.expected[i] {
+					t.Errorf("expected states %v, but received: %v", tc.expected, states)
+				}
+			}
+		})
+	}
+}
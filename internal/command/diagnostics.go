@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"log"
+	"path"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+)
+
+// converts diagnostics parsed from run logs, file names are relative to the uploaded
+// configuration and prefixed with directory, so they resolve from the repository root
+func toPlatformDiagnostics(diags []*cloud.Diagnostic, directory string) []*environment.Diagnostic {
+	result := make([]*environment.Diagnostic, 0, len(diags))
+	for _, d := range diags {
+		diag := &environment.Diagnostic{
+			Severity: d.Severity,
+			Summary:  d.Summary,
+			Detail:   d.Detail,
+		}
+		if d.Range != nil && d.Range.Filename != "" {
+			diag.Filename = path.Join(directory, d.Range.Filename)
+			diag.Line = d.Range.Start.Line
+			diag.EndLine = d.Range.End.Line
+			diag.Column = d.Range.Start.Column
+			diag.EndColumn = d.Range.End.Column
+		}
+		result = append(result, diag)
+	}
+	return result
+}
+
+// annotates source files with diagnostics from run logs, if supported by the platform
+func (c *Meta) reportDiagnostics(runLog *cloud.RunLog, directory string) {
+	if runLog == nil || c.env == nil || c.env.Context == nil {
+		return
+	}
+	reporter, ok := c.env.Context.(environment.DiagnosticReporter)
+	if !ok {
+		return
+	}
+	if err := reporter.ReportDiagnostics(toPlatformDiagnostics(runLog.Diagnostics, directory)); err != nil {
+		log.Printf("[ERROR] problem reporting diagnostics to platform: %s", err.Error())
+	}
+}
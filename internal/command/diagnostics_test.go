@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+func TestToPlatformDiagnostics(t *testing.T) {
+	diags := []*cloud.Diagnostic{
+		{
+			Severity: cloud.DiagnosticError,
+			Summary:  "Unsupported argument",
+			Detail:   "An argument named \"foo\" is not expected here.",
+			Range: &cloud.DiagnosticRange{
+				Filename: "main.tf",
+				Start:    cloud.DiagnosticPos{Line: 3, Column: 3},
+				End:      cloud.DiagnosticPos{Line: 3, Column: 6},
+			},
+		},
+		{
+			Severity: cloud.DiagnosticWarning,
+			Summary:  "Deprecated",
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		directory string
+		filename  string
+	}{
+		{name: "repository-root", directory: "", filename: "main.tf"},
+		{name: "sub-directory", directory: "./infra/prod/", filename: "infra/prod/main.tf"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := toPlatformDiagnostics(diags, tc.directory)
+			if len(result) != len(diags) {
+				t.Fatalf("expected %d diagnostics, but received: %d", len(diags), len(result))
+			}
+			if result[0].Filename != tc.filename {
+				t.Errorf("expected filename %q, but received: %q", tc.filename, result[0].Filename)
+			}
+			if result[0].Line != 3 || result[0].EndLine != 3 || result[0].Column != 3 || result[0].EndColumn != 6 {
+				t.Errorf("unexpected diagnostic position: %+v", result[0])
+			}
+			if result[1].Filename != "" || result[1].Severity != cloud.DiagnosticWarning {
+				t.Errorf("unexpected diagnostic: %+v", result[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// runLogTail streams the plan or apply log of a run while its status is polled,
+// a nil *runLogTail does not stream and logs are read once the run completed
+type runLogTail struct {
+	// opens the stream once the plan or apply of run started, returns nil until then
+	open   func(run *tfe.Run) *cloud.LogStream
+	stream *cloud.LogStream
+}
+
+func (c *Meta) newPlanLogTail(enabled bool) *runLogTail {
+	if !enabled {
+		return nil
+	}
+	return &runLogTail{open: func(run *tfe.Run) *cloud.LogStream {
+		if !cloud.PlanStarted(run) {
+			return nil
+		}
+		return c.cloud.StreamPlanLogs(c.appCtx, run.Plan.ID)
+	}}
+}
+
+func (c *Meta) newApplyLogTail(enabled bool) *runLogTail {
+	if !enabled {
+		return nil
+	}
+	return &runLogTail{open: func(run *tfe.Run) *cloud.LogStream {
+		if !cloud.ApplyStarted(run) {
+			return nil
+		}
+		return c.cloud.StreamApplyLogs(c.appCtx, run.Apply.ID)
+	}}
+}
+
+// starts streaming once the log is available, used as the status change callback of polling loops
+func (t *runLogTail) update(run *tfe.Run) {
+	if t == nil || t.stream != nil || run == nil {
+		return
+	}
+	t.stream = t.open(run)
+}
+
+// waits for the streamed log to complete, streamed is false when the log could not be streamed and has to be read instead.
+// Polling that timed out or was interrupted leaves the run in progress, the stream is canceled instead of waiting for it.
+func (t *runLogTail) wait(pollErr error) (runLog *cloud.RunLog, streamed bool, err error) {
+	if t == nil || t.stream == nil {
+		return nil, false, nil
+	}
+
+	var timeoutErr *cloud.RetryTimeoutError
+	if errors.As(pollErr, &timeoutErr) || errors.Is(pollErr, context.Canceled) || errors.Is(pollErr, context.DeadlineExceeded) {
+		t.stream.Cancel()
+	}
+
+	runLog, err = t.stream.Wait()
+	if runLog == nil {
+		if err != nil {
+			log.Printf("[DEBUG] unable to stream log, reading it instead: %s", err.Error())
+		}
+		return nil, false, nil
+	}
+	return runLog, true, err
+}
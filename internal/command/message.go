@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/go-tfe"
+)
+
+const (
+	// used for run messages when running in a CI platform and no message is provided
+	defaultRunMessageTemplate = "Triggered from HCP Terraform CI by Author ({{.Author}}) for SHA ({{.SHAShort}})"
+	// used for run messages outside of CI
+	defaultRunMessage = "Triggered from HCP Terraform CI"
+)
+
+// messageData is available to run message, comment and justification templates.
+// Values that cannot be resolved for the current platform or command are left empty.
+type messageData struct {
+	// CI platform name, eg. GitHub
+	Platform string
+	// pipeline identifier of the CI platform
+	ID          string
+	SHA         string
+	SHAShort    string
+	Author      string
+	Ref         string
+	Repository  string
+	JobURL      string
+	PullRequest int
+	// web URL of the pull request
+	PullRequestURL string
+
+	Workspace              string
+	ConfigurationVersionID string
+	RunID                  string
+}
+
+// resolves template data from the CI context, with details of the command
+func (c *Meta) newMessageData(workspace string, configurationVersionID string, runID string) *messageData {
+	data := &messageData{
+		Workspace:              workspace,
+		ConfigurationVersionID: configurationVersionID,
+		RunID:                  runID,
+	}
+	if c.env == nil || c.env.Context == nil {
+		return data
+	}
+
+	ctx := c.env.Context
+	data.Platform = string(c.env.PlatformType)
+	data.ID = ctx.ID()
+	data.SHA = ctx.SHA()
+	data.SHAShort = ctx.SHAShort()
+	data.Author = ctx.Author()
+	data.Ref = ctx.Ref()
+	data.Repository = ctx.RepositoryURL()
+	data.JobURL = ctx.JobURL()
+	if pr := ctx.PullRequest(); pr != nil {
+		data.PullRequest = pr.Number
+		data.PullRequestURL = pr.URL
+	}
+	return data
+}
+
+// executes text/template tmpl with data, text without actions is returned as is
+func renderMessage(tmpl string, data *messageData) (string, error) {
+	if !strings.Contains(tmpl, "{{") {
+		return tmpl, nil
+	}
+	t, err := template.New("message").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid message template: %w", err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("error rendering message template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// renders comment template with details of run, empty comments are returned as is
+func (c *Meta) renderRunComment(comment string, run *tfe.Run) (string, error) {
+	if comment == "" {
+		return "", nil
+	}
+	data := c.newMessageData("", "", run.ID)
+	if run.Workspace != nil {
+		data.Workspace = run.Workspace.Name
+	}
+	if run.ConfigurationVersion != nil {
+		data.ConfigurationVersionID = run.ConfigurationVersion.ID
+	}
+	return renderMessage(comment, data)
+}
+
+// resolves a template provided inline or as a file, only one of them can be set
+func readMessageTemplate(tmpl string, file string) (string, error) {
+	if file == "" {
+		return tmpl, nil
+	}
+	if tmpl != "" {
+		return "", fmt.Errorf("only one of -message-template or -message-template-file can be set")
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("error reading message template file %q: %w", file, err)
+	}
+	return string(content), nil
+}
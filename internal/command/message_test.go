@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/environment"
+)
+
+// static CI context for command tests
+type testContext struct {
+	environment.Common
+	pr *environment.PullRequest
+}
+
+func (t *testContext) ID() string            { return "gha-1-2" }
+func (t *testContext) SHA() string           { return "6c1b9f2a8d3e" }
+func (t *testContext) SHAShort() string      { return "6c1b9f2" }
+func (t *testContext) Author() string        { return "octocat" }
+func (t *testContext) Ref() string           { return "feature/network" }
+func (t *testContext) RepositoryURL() string { return "https://github.com/octocat/infra" }
+func (t *testContext) JobURL() string        { return "https://github.com/octocat/infra/actions/runs/1" }
+func (t *testContext) PullRequest() *environment.PullRequest {
+	return t.pr
+}
+func (t *testContext) SetOutput(environment.OutputMap) {}
+func (t *testContext) CloseOutput() error              { return nil }
+
+func TestRenderMessage(t *testing.T) {
+	data := &messageData{
+		SHAShort:               "6c1b9f2",
+		Author:                 "octocat",
+		PullRequest:            7,
+		Workspace:              "prod",
+		ConfigurationVersionID: "cv-123",
+	}
+
+	testCases := []struct {
+		name     string
+		tmpl     string
+		expected string
+		err      string
+	}{
+		{
+			name:     "plain-text",
+			tmpl:     "Deploy approved by ops",
+			expected: "Deploy approved by ops",
+		},
+		{
+			name:     "fields",
+			tmpl:     "{{.Workspace}}: PR #{{.PullRequest}} by {{.Author}} at {{.SHAShort}} ({{.ConfigurationVersionID}})",
+			expected: "prod: PR #7 by octocat at 6c1b9f2 (cv-123)",
+		},
+		{
+			name:     "conditional",
+			tmpl:     "{{if .PullRequest}}PR #{{.PullRequest}}{{else}}{{.Ref}}{{end}}\n",
+			expected: "PR #7",
+		},
+		{
+			name: "invalid-template",
+			tmpl: "{{.Workspace",
+			err:  "invalid message template",
+		},
+		{
+			name: "unknown-field",
+			tmpl: "{{.Branch}}",
+			err:  "error rendering message template",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := renderMessage(tc.tmpl, data)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("expected error containing %q, but received: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %q, but received: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCreateRunCommand_RunMessage(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "message.tmpl")
+	if err := os.WriteFile(templateFile, []byte("{{.Workspace}} from {{.JobURL}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ci := &environment.CI{
+		PlatformType: environment.GitHub,
+		Context:      &testContext{pr: &environment.PullRequest{Number: 7, URL: "https://github.com/octocat/infra/pull/7"}},
+	}
+
+	testCases := []struct {
+		name     string
+		env      *environment.CI
+		cmd      CreateRunCommand
+		expected string
+		err      string
+	}{
+		{
+			name:     "default-outside-ci",
+			env:      &environment.CI{PlatformType: environment.Other},
+			expected: "Triggered from HCP Terraform CI",
+		},
+		{
+			name:     "default",
+			env:      ci,
+			expected: "Triggered from HCP Terraform CI by Author (octocat) for SHA (6c1b9f2)",
+		},
+		{
+			name:     "template",
+			env:      ci,
+			cmd:      CreateRunCommand{Workspace: "prod", MessageTemplate: "{{.Platform}} {{.Ref}} {{.PullRequestURL}}"},
+			expected: "GitHub feature/network https://github.com/octocat/infra/pull/7",
+		},
+		{
+			name:     "template-file",
+			env:      ci,
+			cmd:      CreateRunCommand{Workspace: "prod", MessageTemplateFile: templateFile},
+			expected: "prod from https://github.com/octocat/infra/actions/runs/1",
+		},
+		{
+			name: "template-and-file",
+			env:  ci,
+			cmd:  CreateRunCommand{MessageTemplate: "{{.SHA}}", MessageTemplateFile: templateFile},
+			err:  "only one of -message-template or -message-template-file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.cmd
+			c.Meta = &Meta{env: tc.env}
+			actual, err := c.runMessage()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("expected error containing %q, but received: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %q, but received: %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMeta_RenderRunComment(t *testing.T) {
+	m := &Meta{env: &environment.CI{PlatformType: environment.GitHub, Context: &testContext{}}}
+	run := &tfe.Run{
+		ID:                   "run-abc123",
+		Workspace:            &tfe.Workspace{Name: "prod"},
+		ConfigurationVersion: &tfe.ConfigurationVersion{ID: "cv-123"},
+	}
+
+	actual, err := m.renderRunComment("Applied {{.RunID}} in {{.Workspace}} by {{.Author}}, see {{.JobURL}}", run)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := "Applied run-abc123 in prod by octocat, see https://github.com/octocat/infra/actions/runs/1"
+	if actual != expected {
+		t.Errorf("expected %q, but received: %q", expected, actual)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
 
+	"github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/tfci/internal/cloud"
 	"github.com/hashicorp/tfci/internal/environment"
 )
@@ -22,6 +24,10 @@ const (
 	Error   Status = "Error"
 	Timeout Status = "Timeout"
 	Noop    Status = "Noop"
+	// plan check rules are violated
+	Violation Status = "Violation"
+	// run apply safeguards are violated, the run is not applied
+	Blocked Status = "Blocked"
 )
 
 type Writer interface {
@@ -47,6 +53,11 @@ type Meta struct {
 	writer Writer
 	// flag to prevent non-json messages to stdout
 	json bool
+	// optional file that outputs are written to, alongside platform output
+	outputFile       string
+	outputFileFormat environment.OutputFormat
+	// run links resolved during the command, keyed by run id
+	runLinks map[string]string
 }
 
 func (c *Meta) setupCmd(args []string, flags *flag.FlagSet) error {
@@ -77,6 +88,13 @@ func (c *Meta) emitFlagOptions() {
 	c.writer.UseJson(c.json)
 	// configure json option for cloud writer
 	c.cloud.UseJson(c.json)
+	// keep stdout for the json result, platforms still read commands from stderr
+	if c.env == nil || c.env.Context == nil || !c.json {
+		return
+	}
+	if cw, ok := c.env.Context.(environment.CommandWriter); ok {
+		cw.SetCommandWriter(os.Stderr)
+	}
 }
 
 func (c *Meta) resolveStatus(err error) Status {
@@ -91,6 +109,22 @@ func (c *Meta) resolveStatus(err error) Status {
 	return Success
 }
 
+// resolves the HCP Terraform link of run once per command, empty when it cannot be resolved
+func (c *Meta) runLink(run *tfe.Run) string {
+	if link, ok := c.runLinks[run.ID]; ok {
+		return link
+	}
+	link, err := c.cloud.RunLink(c.appCtx, c.organization, run)
+	if err != nil {
+		log.Printf("[ERROR] problem resolving run link: %s", err.Error())
+	}
+	if c.runLinks == nil {
+		c.runLinks = make(map[string]string)
+	}
+	c.runLinks[run.ID] = link
+	return link
+}
+
 // adds new output value to map as &OutputMessage{}
 func (c *Meta) addOutput(name string, value string) {
 	c.messages[name] = newOutputMessage(name, value, defaultOutputOpts)
@@ -136,6 +170,13 @@ func (c *Meta) closeOutput() string {
 		c.env.Context.CloseOutput()
 	}
 
+	// write outputs to file when configured, regardless of platform
+	if c.outputFile != "" {
+		if err := environment.WriteOutputFile(c.outputFile, c.outputFileFormat, platOutput); err != nil {
+			log.Printf("[ERROR] problem writing output file: '%s', with: %s", c.outputFile, err.Error())
+		}
+	}
+
 	outJson, err := json.MarshalIndent(stdOutput, "", "  ")
 	if err != nil {
 		return string(err.Error())
@@ -155,6 +196,13 @@ func WithWriter(w Writer) func(*Meta) {
 	}
 }
 
+func WithOutputFile(path string, format environment.OutputFormat) func(*Meta) {
+	return func(m *Meta) {
+		m.outputFile = path
+		m.outputFileFormat = format
+	}
+}
+
 func NewMetaOpts(ctx context.Context, tfeClient *cloud.Cloud, ciEnv *environment.CI, setters ...func(*Meta)) *Meta {
 	m := &Meta{
 		cloud:    tfeClient,
@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+func TestMeta_CloseOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tfci.env")
+	meta := NewMetaOpts(context.Background(), nil, &environment.CI{PlatformType: environment.Other}, WithOutputFile(path, environment.OutputFormatDotenv))
+
+	meta.addOutput("status", string(Success))
+	meta.addOutputWithOpts("run_id", "run-abc123", &outputOpts{
+		stdOut:      false,
+		platformOut: true,
+	})
+	meta.addOutputWithOpts("stdout_only", "value", &outputOpts{
+		stdOut:      true,
+		platformOut: false,
+	})
+
+	meta.closeOutput()
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	expected := "run_id=run-abc123\nstatus=Success\n"
+	if string(contents) != expected {
+		t.Errorf("expected %q, but received: %q", expected, string(contents))
+	}
+}
+
+// writes a logging command for every output, like agents that read output variables from the job log
+type commandContext struct {
+	testContext
+	commands io.Writer
+	output   environment.OutputMap
+}
+
+func (c *commandContext) SetCommandWriter(w io.Writer)      { c.commands = w }
+func (c *commandContext) SetOutput(o environment.OutputMap) { c.output = o }
+func (c *commandContext) CloseOutput() error {
+	for k, v := range c.output {
+		fmt.Fprintf(c.commands, "##vso[task.setvariable variable=%s;isOutput=true]%s\n", k, v.String())
+	}
+	return nil
+}
+
+func TestMeta_JsonStdout(t *testing.T) {
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	t.Cleanup(func() { os.Stdout, os.Stderr = origStdout, origStderr })
+
+	w := writer.NewWriter(&cli.BasicUi{Writer: stdout, ErrorWriter: stderr})
+	ctx := &commandContext{commands: stdout}
+	meta := NewMetaOpts(context.Background(), cloud.NewCloud(&tfe.Client{}, w), &environment.CI{PlatformType: environment.AzureDevOps, Context: ctx}, WithWriter(w))
+	if err := meta.setupCmd([]string{"-json"}, meta.flagSet("test")); err != nil {
+		t.Fatal(err)
+	}
+
+	meta.addOutput("status", string(Success))
+	meta.writer.OutputResult(meta.closeOutput())
+
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]string
+	if err := json.Unmarshal(out, &result); err != nil || result["status"] != string(Success) {
+		t.Errorf("expected stdout to only contain the json result, but received: %q", out)
+	}
+	commands, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(commands) != "##vso[task.setvariable variable=status;isOutput=true]Success\n" {
+		t.Errorf("expected logging command on stderr, but received: %q", commands)
+	}
+}
+
+func TestMeta_JsonWithoutContext(t *testing.T) {
+	w := writer.NewWriter(cli.NewMockUi())
+	for name, env := range map[string]*environment.CI{"no-environment": nil, "no-context": {PlatformType: environment.Other}} {
+		t.Run(name, func(t *testing.T) {
+			meta := NewMetaOpts(context.Background(), cloud.NewCloud(&tfe.Client{}, w), env, WithWriter(w))
+			if err := meta.setupCmd([]string{"-json"}, meta.flagSet("test")); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
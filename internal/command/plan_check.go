@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/plancheck"
+)
+
+// exit code when plan rules or apply safeguards are violated, to tell violations apart from errors
+const violationExitCode = 2
+
+type CheckPlanCommand struct {
+	*Meta
+
+	PlanID string
+	// JSON execution plan on disk, eg. from `terraform show -json` or `plan output -json-file`
+	PlanFile  string
+	RulesFile string
+	Labels    []string
+}
+
+func (c *CheckPlanCommand) flags() *flag.FlagSet {
+	f := c.flagSet("plan check")
+	f.StringVar(&c.PlanID, "plan", "", "The plan ID of the JSON execution plan to check.")
+	f.StringVar(&c.PlanFile, "plan-file", "", "Path of a JSON execution plan to check, instead of reading it from HCP Terraform. Does not require an API token.")
+	f.StringVar(&c.RulesFile, "rules", "", "Path of the JSON rules file.")
+	f.Var((*flagStringSlice)(&c.Labels), "label", "Label for rules requiring labels, in addition to the pull request labels. Pull request labels are read from the event payload, rerun the workflow on the labeled event to pick up new labels. You can use this option multiple times.")
+	return f
+}
+
+// IsOfflinePlanCheck reports whether plan check args only read local files, a plan file without a plan id
+func IsOfflinePlanCheck(args []string) bool {
+	c := &CheckPlanCommand{Meta: &Meta{}}
+	if err := c.flags().Parse(args); err != nil {
+		return false
+	}
+	return c.PlanFile != "" && c.PlanID == ""
+}
+
+func (c *CheckPlanCommand) Run(args []string) int {
+	if err := c.setupCmd(args, c.flags()); err != nil {
+		return 1
+	}
+
+	if c.RulesFile == "" {
+		return c.checkError("checking a plan requires a rules file (use --rules)")
+	}
+	if (c.PlanID == "") == (c.PlanFile == "") {
+		return c.checkError("checking a plan requires exactly one of --plan or --plan-file")
+	}
+
+	rules, err := plancheck.LoadRules(c.RulesFile)
+	if err != nil {
+		return c.checkError(err.Error())
+	}
+
+	data, err := c.readPlanJSON()
+	if err != nil {
+		return c.checkError(fmt.Sprintf("error reading JSON execution plan: %s", err.Error()))
+	}
+	changes, err := cloud.ParsePlanChanges(data)
+	if err != nil {
+		return c.checkError(err.Error())
+	}
+
+	violations := rules.Evaluate(changes, c.labels())
+
+	c.addOutput("change_count", fmt.Sprint(len(changes)))
+	c.addOutput("violation_count", fmt.Sprint(len(violations)))
+	c.addOutputWithOpts("violations", violations, &outputOpts{
+		stdOut:      true,
+		multiLine:   true,
+		platformOut: true,
+	})
+
+	if len(violations) == 0 {
+		c.writer.Output(fmt.Sprintf("Plan check passed: %d rule(s) evaluated for %d resource change(s)", len(rules.Rules), len(changes)))
+		c.addOutput("status", string(Success))
+		c.writer.OutputResult(c.closeOutput())
+		return 0
+	}
+
+	c.writer.Output(fmt.Sprintf("Plan check failed with %d violation(s):", len(violations)))
+	for _, v := range violations {
+		c.writer.Output(fmt.Sprintf("  [%s] %s", v.Rule, v.Message))
+	}
+	c.addOutput("status", string(Violation))
+	c.writer.OutputResult(c.closeOutput())
+	return violationExitCode
+}
+
+func (c *CheckPlanCommand) checkError(msg string) int {
+	c.addOutput("status", string(Error))
+	c.closeOutput()
+	c.writer.ErrorResult(msg)
+	return 1
+}
+
+// reads the plan file, or the unredacted JSON execution plan so changes of sensitive attributes are detected
+func (c *CheckPlanCommand) readPlanJSON() ([]byte, error) {
+	if c.PlanFile != "" {
+		return os.ReadFile(c.PlanFile)
+	}
+	return c.cloud.GetPlanJSON(c.appCtx, cloud.GetPlanJSONOptions{
+		PlanID:        c.PlanID,
+		ShowSensitive: true,
+	})
+}
+
+// labels of the flags and pull request
+func (c *CheckPlanCommand) labels() []string {
+	labels := append([]string{}, c.Labels...)
+	if c.env != nil && c.env.Context != nil {
+		if pr := c.env.Context.PullRequest(); pr != nil {
+			labels = append(labels, pr.Labels...)
+		}
+	}
+	return labels
+}
+
+func (c *CheckPlanCommand) Help() string {
+	helpText := `
+Usage: tfci [global options] plan check [options]
+
+	Checks the resource changes of a JSON execution plan against a local rules file.
+
+Global Options:
+
+	-hostname       The hostname of a Terraform Enterprise installation, if using Terraform Enterprise. Defaults to "app.terraform.io".
+
+	-token          The token used to authenticate with HCP Terraform. Defaults to reading "TF_API_TOKEN" environment variable.
+
+	-organization   HCP Terraform Organization Name.
+
+Options:
+
+	-plan        The plan ID of the JSON execution plan to check.
+
+	-plan-file   Path of a JSON execution plan to check, instead of reading it from HCP Terraform. Does not require an API token.
+
+	-rules       Path of the JSON rules file.
+
+	-label       Label for rules requiring labels, in addition to the pull request labels. Pull request labels are read from the event payload, rerun the workflow on the labeled event to pick up new labels. You can use this option multiple times.
+
+Exit Codes:
+
+	0   Success, no rule is violated
+	1   Error (invalid rules file, unreadable plan, API error)
+	2   Rules are violated, violations are returned in the "violations" output
+	`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *CheckPlanCommand) Synopsis() string {
+	return "Checks the resource changes of a plan against local rules"
+}
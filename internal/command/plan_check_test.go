@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/plancheck"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+func TestCheckPlanCommand(t *testing.T) {
+	dir := t.TempDir()
+	planFile := filepath.Join(dir, "tfplan.json")
+	if err := os.WriteFile(planFile, []byte(testResourceChangesPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rulesFile := filepath.Join(dir, "rules.json")
+	if err := os.WriteFile(rulesFile, []byte(`{"rules": [{"name": "keep-databases", "resource_types": ["aws_db_instance"], "actions": ["replace", "delete"], "deny": true}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		status       Status
+		violations   int
+	}{
+		{
+			name:         "violation",
+			args:         []string{"-plan-file", planFile, "-rules", rulesFile},
+			expectedCode: violationExitCode,
+			status:       Violation,
+			violations:   1,
+		},
+		{
+			name:         "missing-rules",
+			args:         []string{"-plan-file", planFile},
+			expectedCode: 1,
+			status:       Error,
+		},
+		{
+			name:         "plan-and-plan-file",
+			args:         []string{"-plan", "plan-123", "-plan-file", planFile, "-rules", rulesFile},
+			expectedCode: 1,
+			status:       Error,
+		},
+		{
+			name:         "invalid-rules",
+			args:         []string{"-plan-file", planFile, "-rules", planFile},
+			expectedCode: 1,
+			status:       Error,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			w := writer.NewWriter(ui)
+			// plan files are checked without HCP Terraform
+			cloudService := cloud.NewCloud(nil, w)
+			meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+			c := &CheckPlanCommand{Meta: meta}
+			if code := c.Run(tc.args); code != tc.expectedCode {
+				t.Fatalf("expected exit code %d, but received: %d, %s", tc.expectedCode, code, ui.ErrorWriter.String())
+			}
+			if v := meta.messages["status"]; v == nil || v.value != string(tc.status) {
+				t.Errorf("expected status %q, but received: %+v", tc.status, v)
+			}
+			if tc.violations == 0 {
+				return
+			}
+
+			violations, ok := meta.messages["violations"].value.([]*plancheck.Violation)
+			if !ok || len(violations) != tc.violations || violations[0].Address != "aws_db_instance.main" {
+				t.Errorf("unexpected violations: %+v", meta.messages["violations"].value)
+			}
+			if !strings.Contains(ui.OutputWriter.String(), `"violations": [`) {
+				t.Errorf("expected violations in command result, but received:\n%s", ui.OutputWriter.String())
+			}
+		})
+	}
+}
+
+func TestCheckPlanCommand_Labels(t *testing.T) {
+	stub := &planStub{planJSON: testResourceChangesPlan}
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(rulesFile, []byte(`{"rules": [{"name": "db-review", "resource_types": ["aws_db_*"], "require_labels": ["db-approved"]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	cloudService := cloud.NewCloud(nil, w)
+	cloudService.PlanService = stub
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+	c := &CheckPlanCommand{Meta: meta}
+	if code := c.Run([]string{"-plan", "plan-123", "-rules", rulesFile, "-label", "db-approved"}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.OutputWriter.String())
+	}
+	if stub.options.PlanID != "plan-123" {
+		t.Errorf("expected JSON execution plan of plan-123, but received: %+v", stub.options)
+	}
+}
+
+func TestIsOfflinePlanCheck(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected bool
+	}{
+		{args: []string{"-rules", "rules.json", "-plan-file", "tfplan.json"}, expected: true},
+		{args: []string{"--plan-file=tfplan.json", "--rules=rules.json", "-json"}, expected: true},
+		// flag values that look like the plan-file flag are not the flag
+		{args: []string{"-rules", "plan-file-rules.json", "-plan", "plan-123"}, expected: false},
+		{args: []string{"-rules", "rules.json", "-plan-file", "tfplan.json", "-plan", "plan-123"}, expected: false},
+		{args: []string{"-plan-file"}, expected: false},
+		{args: []string{"-unknown", "-plan-file", "tfplan.json"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		if actual := IsOfflinePlanCheck(tc.args); actual != tc.expected {
+			t.Errorf("expected %t for %v, but received: %t", tc.expected, tc.args, actual)
+		}
+	}
+}
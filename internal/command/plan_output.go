@@ -6,20 +6,33 @@ package command
 import (
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
 )
 
 type OutputPlanCommand struct {
 	*Meta
 
 	PlanID string
+	// path the JSON execution plan is written to
+	JSONFile      string
+	ShowSensitive bool
+	// render a table of resource changes from the JSON execution plan
+	ResourceChanges bool
+
+	changes []*cloud.ResourceChange
 }
 
 func (c *OutputPlanCommand) flags() *flag.FlagSet {
 	f := c.flagSet("plan output")
 	f.StringVar(&c.PlanID, "plan", "", "The plan ID to retrieve JSON execution plan.")
+	f.StringVar(&c.JSONFile, "json-file", "", "Writes the JSON execution plan to a file, eg. for policy and cost tools or as a pipeline artifact.")
+	f.BoolVar(&c.ShowSensitive, "show-sensitive", false, "Keeps sensitive values in the JSON execution plan, they are redacted by default.")
+	f.BoolVar(&c.ResourceChanges, "resource-changes", false, "Prints a table of resource changes from the JSON execution plan and adds it to summaries and the `resource_changes` output. Sensitive values are masked.")
 
 	return f
 }
@@ -38,12 +51,49 @@ func (c *OutputPlanCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.JSONFile != "" {
+		if err := c.writePlanJSON(); err != nil {
+			c.addOutput("status", string(Error))
+			c.addPlanDetails(plan)
+			c.writer.ErrorResult(fmt.Sprintf("error writing JSON execution plan: %s", err.Error()))
+			c.writer.OutputResult(c.closeOutput())
+			return 1
+		}
+	}
+
+	if c.ResourceChanges && plan.Status == tfe.PlanFinished {
+		c.changes = c.readResourceChanges(plan.ID)
+	}
+
 	c.addOutput("status", string(Success))
 	c.addPlanDetails(plan)
 	c.writer.OutputResult(c.closeOutput())
 	return 0
 }
 
+// downloads the JSON execution plan to the json file
+func (c *OutputPlanCommand) writePlanJSON() error {
+	data, err := c.cloud.GetPlanJSON(c.appCtx, cloud.GetPlanJSONOptions{
+		PlanID:        c.PlanID,
+		ShowSensitive: c.ShowSensitive,
+	})
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(c.JSONFile); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(c.JSONFile, data, 0644); err != nil {
+		return err
+	}
+
+	c.addOutput("json_file", c.JSONFile)
+	return nil
+}
+
 func (c *OutputPlanCommand) addPlanDetails(plan *tfe.Plan) {
 	if plan == nil {
 		return
@@ -59,6 +109,13 @@ func (c *OutputPlanCommand) addPlanDetails(plan *tfe.Plan) {
 		multiLine:   true,
 		platformOut: true,
 	})
+
+	c.reportPlan(plan)
+	c.writeSummary(&runSummary{
+		title:   fmt.Sprintf("HCP Terraform Plan `%s`", plan.ID),
+		plan:    plan,
+		changes: c.changes,
+	})
 }
 
 func (c *OutputPlanCommand) Help() string {
@@ -77,7 +134,13 @@ Global Options:
 
 Options:
 
-	-plan           Returns the plan details for the provided Plan ID.
+	-plan            Returns the plan details for the provided Plan ID.
+
+	-json-file       Writes the JSON execution plan to a file, eg. for policy and cost tools or as a pipeline artifact.
+
+	-show-sensitive  Keeps sensitive values in the JSON execution plan, they are redacted by default.
+
+	-resource-changes  Prints a table of resource changes from the JSON execution plan and adds it to summaries and the "resource_changes" output. Sensitive values are masked.
 	`
 	return strings.TrimSpace(helpText)
 }
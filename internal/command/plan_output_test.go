@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// returns a finished plan and its JSON execution plan without calling HCP Terraform
+type planStub struct {
+	options cloud.GetPlanJSONOptions
+	// returned JSON execution plan, defaults to an empty plan
+	planJSON string
+}
+
+func (s *planStub) GetPlan(_ context.Context, planID string) (*tfe.Plan, error) {
+	return &tfe.Plan{ID: planID, Status: tfe.PlanFinished, ResourceAdditions: 1}, nil
+}
+
+func (s *planStub) GetPlanJSON(_ context.Context, options cloud.GetPlanJSONOptions) ([]byte, error) {
+	s.options = options
+	if s.planJSON != "" {
+		return []byte(s.planJSON), nil
+	}
+	return []byte(`{"format_version":"1.2"}`), nil
+}
+
+func TestOutputPlanCommand_JSONFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans", "tfplan.json")
+
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	stub := &planStub{}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.PlanService = stub
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+	c := &OutputPlanCommand{Meta: meta}
+	if code := c.Run([]string{"-plan", "plan-123", "-json-file", path}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	if stub.options.PlanID != "plan-123" || stub.options.ShowSensitive {
+		t.Errorf("expected redacted JSON execution plan of plan-123, but received: %+v", stub.options)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+	if string(contents) != `{"format_version":"1.2"}` {
+		t.Errorf("unexpected JSON execution plan: %s", contents)
+	}
+	if v := meta.messages["json_file"]; v == nil || v.value != path {
+		t.Errorf("expected json_file output %q, but received: %+v", path, v)
+	}
+}
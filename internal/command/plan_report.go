@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"log"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/environment"
+)
+
+// reports plan resource changes to the platform, if supported
+func (c *Meta) reportPlan(plan *tfe.Plan) {
+	// resource counts are only known once the plan has finished
+	if plan == nil || plan.Status != tfe.PlanFinished || c.env == nil || c.env.Context == nil {
+		return
+	}
+	reporter, ok := c.env.Context.(environment.PlanReporter)
+	if !ok {
+		return
+	}
+	err := reporter.ReportPlan(&environment.PlanReport{
+		Create: plan.ResourceAdditions,
+		Update: plan.ResourceChanges,
+		Delete: plan.ResourceDestructions,
+	})
+	if err != nil {
+		log.Printf("[ERROR] problem reporting plan to platform: %s", err.Error())
+	}
+}
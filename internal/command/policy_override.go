@@ -21,7 +21,7 @@ type PolicyOverrideCommand struct {
 func (c *PolicyOverrideCommand) flags() *flag.FlagSet {
 	f := c.flagSet("policy override")
 	f.StringVar(&c.RunID, "run", "", "HCP Terraform Run ID to override policies for.")
-	f.StringVar(&c.Justification, "justification", "", "Reason for override (minimum 10 characters).")
+	f.StringVar(&c.Justification, "justification", "", "Reason for override (minimum 10 characters). Supports Go text/template fields of the CI context.")
 
 	return f
 }
@@ -46,10 +46,29 @@ func (c *PolicyOverrideCommand) Run(args []string) int {
 		return 1
 	}
 
+	// the run provides workspace and configuration version fields of the justification
+	run, err := c.cloud.GetRun(c.appCtx, cloud.GetRunOptions{
+		RunID: c.RunID,
+	})
+	if err != nil {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult(fmt.Sprintf("unable to read run: %s with: %s", c.RunID, err.Error()))
+		return 1
+	}
+
+	justification, err := c.renderRunComment(c.Justification, run)
+	if err != nil {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult(err.Error())
+		return 1
+	}
+
 	// Apply policy override
 	override, err := c.cloud.OverridePolicy(c.appCtx, cloud.OverridePolicyOptions{
 		RunID:         c.RunID,
-		Justification: c.Justification,
+		Justification: justification,
 	})
 
 	if err != nil {
@@ -154,6 +173,7 @@ Options:
 
 	-justification  Reason for override (required, minimum 10 characters).
 	                Should reference approval source (e.g., incident ticket, change request).
+	                Supports Go text/template fields of the CI context, e.g. {{.JobURL}} and {{.RunID}}.
 
 Exit Codes:
 
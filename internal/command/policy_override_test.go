@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+type overrideRunStub struct {
+	cloud.RunService
+}
+
+func (s *overrideRunStub) GetRun(_ context.Context, options cloud.GetRunOptions) (*tfe.Run, error) {
+	return &tfe.Run{
+		ID:                   options.RunID,
+		Status:               tfe.RunPolicyOverride,
+		Workspace:            &tfe.Workspace{ID: "ws-1", Name: "networking"},
+		ConfigurationVersion: &tfe.ConfigurationVersion{ID: "cv-1"},
+	}, nil
+}
+
+// records the override options, the override details are left out as they link to the run
+type overridePolicyStub struct {
+	cloud.PolicyService
+	options cloud.OverridePolicyOptions
+}
+
+func (s *overridePolicyStub) OverridePolicy(_ context.Context, options cloud.OverridePolicyOptions) (*cloud.PolicyOverride, error) {
+	s.options = options
+	return nil, nil
+}
+
+func TestPolicyOverrideCommand_Justification(t *testing.T) {
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	policies := &overridePolicyStub{}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = &overrideRunStub{}
+	cloudService.PolicyService = policies
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+	c := &PolicyOverrideCommand{Meta: meta}
+	if code := c.Run([]string{"-run", "run-123", "-justification", "Approved for {{.Workspace}} ({{.ConfigurationVersionID}}, {{.RunID}})"}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	expected := "Approved for networking (cv-1, run-123)"
+	if policies.options.Justification != expected {
+		t.Errorf("expected justification %q, but received: %q", expected, policies.options.Justification)
+	}
+}
@@ -96,6 +96,14 @@ func (c *PolicyShowCommand) addPolicyEvaluationDetails(eval *cloud.PolicyEvaluat
 		platformOut: true,
 	})
 
+	// Markdown report for platforms that support job summaries
+	c.writeSummary(&runSummary{
+		title:   "HCP Terraform Policy Evaluation",
+		runID:   eval.RunID,
+		runLink: runLink,
+		policy:  eval,
+	})
+
 	// Human-readable output (when not in JSON mode)
 	if !c.json {
 		c.writer.Output("\n📊 Policy Evaluation Summary")
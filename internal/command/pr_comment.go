@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/github"
+	"github.com/hashicorp/tfci/internal/gitlab"
+)
+
+// publishes comments to the pull request that triggered the pipeline,
+// implementations keep a single comment per key up to date
+type commentPublisher interface {
+	// returns the link to the created or updated comment
+	UpsertComment(ctx context.Context, key string, body string) (string, error)
+}
+
+// hidden marker used to find the previously published comment for key
+func commentMarker(key string) string {
+	return fmt.Sprintf("<!-- tfci:%s -->", key)
+}
+
+type githubCommentPublisher struct {
+	client *github.Client
+	number int
+}
+
+func (p *githubCommentPublisher) UpsertComment(ctx context.Context, key string, body string) (string, error) {
+	comment, err := p.client.UpsertIssueComment(ctx, p.number, commentMarker(key), body)
+	if err != nil {
+		return "", err
+	}
+	return comment.HTMLURL, nil
+}
+
+type gitlabCommentPublisher struct {
+	client     *gitlab.Client
+	iid        int
+	projectURL string
+}
+
+func (p *gitlabCommentPublisher) UpsertComment(ctx context.Context, key string, body string) (string, error) {
+	note, err := p.client.UpsertMergeRequestNote(ctx, p.iid, commentMarker(key), body)
+	if err != nil {
+		return "", err
+	}
+	if p.projectURL == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("%s/-/merge_requests/%d#note_%d", p.projectURL, p.iid, note.ID), nil
+}
+
+// resolves comment publisher for the pull request of the current platform
+func (c *Meta) commentPublisher() (commentPublisher, error) {
+	if c.env == nil || c.env.Context == nil {
+		return nil, fmt.Errorf("pull request comments are not supported outside of CI")
+	}
+
+	switch ctx := c.env.Context.(type) {
+	case *environment.GitHubContext:
+		number := ctx.PullRequestNumber()
+		if number == 0 {
+			return nil, fmt.Errorf("workflow was not triggered by a pull request")
+		}
+		client, err := github.NewClient(ctx.APIURL(), ctx.Token(), ctx.Repository())
+		if err != nil {
+			return nil, err
+		}
+		return &githubCommentPublisher{client: client, number: number}, nil
+	case *environment.GitLabContext:
+		iid := ctx.MergeRequestIID()
+		if iid == 0 {
+			return nil, fmt.Errorf("pipeline does not run for a merge request")
+		}
+		client, err := gitlab.NewClient(ctx.APIURL(), ctx.ProjectID(), ctx.Token())
+		if err != nil {
+			return nil, err
+		}
+		return &gitlabCommentPublisher{client: client, iid: iid, projectURL: ctx.ProjectURL()}, nil
+	default:
+		return nil, fmt.Errorf("pull request comments are not supported for platform: %s", c.env.PlatformType)
+	}
+}
+
+// renders the run summary with a collapsible excerpt of the log
+func runCommentBody(summary *runSummary, runLog *cloud.RunLog) string {
+	var b strings.Builder
+	b.WriteString(summary.markdown())
+	if runLog != nil {
+		if excerpt := runLog.Excerpt(); excerpt != "" {
+			fence := codeFence(excerpt)
+			b.WriteString("\n<details><summary>Plan log</summary>\n\n" + fence + "text\n")
+			b.WriteString(excerpt)
+			b.WriteString("\n" + fence + "\n\n</details>\n")
+		}
+	}
+	return b.String()
+}
+
+// returns a backtick fence longer than the longest backtick run of content,
+// so that content can not close the code block
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// creates or updates the pull request comment for workspace, failures are reported but do not fail the command
+func (c *Meta) publishRunComment(workspace string, summary *runSummary, runLog *cloud.RunLog) {
+	if summary == nil {
+		return
+	}
+
+	publisher, err := c.commentPublisher()
+	if err != nil {
+		c.writer.Error(fmt.Sprintf("unable to publish pull request comment: %s", err.Error()))
+		return
+	}
+
+	commentSummary := *summary
+	commentSummary.title = fmt.Sprintf("%s `%s`", summary.title, workspace)
+
+	link, err := publisher.UpsertComment(c.appCtx, fmt.Sprintf("run-summary workspace=%s", workspace), runCommentBody(&commentSummary, runLog))
+	if err != nil {
+		c.writer.Error(fmt.Sprintf("unable to publish pull request comment: %s", err.Error()))
+		return
+	}
+	if link != "" {
+		c.addOutput("comment_link", link)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/tfci/internal/environment"
+)
+
+func TestRunCommentBody(t *testing.T) {
+	summary := &runSummary{title: "HCP Terraform Run `prod`", runID: "run-abc123"}
+
+	body := runCommentBody(summary, nil)
+	if !strings.HasPrefix(body, "### HCP Terraform Run `prod`") {
+		t.Errorf("expected comment to start with summary, received: %q", body)
+	}
+	if strings.Contains(body, "<details>") {
+		t.Errorf("expected no log excerpt without plan log, received: %q", body)
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	cases := map[string]string{
+		"Plan: 1 to add, 0 to change, 0 to destroy.": "```",
+		"user_data = \"`whoami`\"":                   "```",
+		"description = \"```\\n</details>\"":         "````",
+		"a ````` b ``` c":                            "``````",
+	}
+	for content, expected := range cases {
+		if actual := codeFence(content); actual != expected {
+			t.Errorf("expected fence %s for %q, but received: %s", expected, content, actual)
+		}
+	}
+}
+
+func TestMeta_CommentPublisher(t *testing.T) {
+	testCases := []struct {
+		name string
+		env  *environment.CI
+		err  string
+	}{
+		{
+			name: "outside-ci",
+			env:  &environment.CI{PlatformType: environment.Other},
+			err:  "not supported outside of CI",
+		},
+		{
+			name: "unsupported-platform",
+			env:  &environment.CI{PlatformType: environment.Jenkins, Context: &environment.JenkinsContext{}},
+			err:  "not supported for platform: Jenkins",
+		},
+		{
+			name: "github-push",
+			env:  &environment.CI{PlatformType: environment.GitHub, Context: &environment.GitHubContext{}},
+			err:  "not triggered by a pull request",
+		},
+		{
+			name: "gitlab-branch-pipeline",
+			env:  &environment.CI{PlatformType: environment.GitLab, Context: &environment.GitLabContext{}},
+			err:  "does not run for a merge request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Meta{env: tc.env}
+			_, err := m.commentPublisher()
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, but received: %v", tc.err, err)
+			}
+		})
+	}
+}
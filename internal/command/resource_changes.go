@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// shown in place of the module address of root module resources
+const rootModuleName = "(root)"
+
+// titles of change table groups
+var changeActionTitles = map[string]string{
+	cloud.ChangeCreate:  "Create",
+	cloud.ChangeUpdate:  "Update",
+	cloud.ChangeReplace: "Replace",
+	cloud.ChangeDelete:  "Delete",
+	cloud.ChangeRead:    "Read",
+}
+
+// fetches the JSON execution plan of a finished plan and adds its resource changes as `resource_changes` output,
+// with `-json` the change table is logged instead of printed. Failures are reported without failing the command,
+// as reading JSON execution plans requires additional workspace permissions.
+func (c *Meta) readResourceChanges(planID string) []*cloud.ResourceChange {
+	// sensitive values are masked once changes are resolved, redacted plans hide changes of sensitive attributes
+	data, err := c.cloud.GetPlanJSON(c.appCtx, cloud.GetPlanJSONOptions{
+		PlanID:        planID,
+		ShowSensitive: true,
+	})
+	if err != nil {
+		c.writer.Error(fmt.Sprintf("unable to read resource changes: %s", err.Error()))
+		return nil
+	}
+	changes, err := cloud.ParsePlanChanges(data)
+	if err != nil {
+		c.writer.Error(fmt.Sprintf("unable to read resource changes: %s", err.Error()))
+		return nil
+	}
+
+	c.writer.Output(changeTableText(changes))
+	c.addOutputWithOpts("resource_changes", changes, &outputOpts{
+		stdOut:      true,
+		multiLine:   true,
+		platformOut: true,
+	})
+	return changes
+}
+
+// groups changes by action, changes are already sorted by action, module and address
+func groupChanges(changes []*cloud.ResourceChange) map[string][]*cloud.ResourceChange {
+	groups := map[string][]*cloud.ResourceChange{}
+	for _, change := range changes {
+		groups[change.Action] = append(groups[change.Action], change)
+	}
+	return groups
+}
+
+func moduleName(change *cloud.ResourceChange) string {
+	if change.Module == "" {
+		return rootModuleName
+	}
+	return change.Module
+}
+
+func formatAttributeChange(attr *cloud.AttributeChange, format string) string {
+	text := fmt.Sprintf(format, attr.Name, attr.Before, attr.After)
+	if attr.ForcesReplacement {
+		text += " (forces replacement)"
+	}
+	return text
+}
+
+// renders changes as terminal table, grouped by action and module
+func changeTableText(changes []*cloud.ResourceChange) string {
+	if len(changes) == 0 {
+		return "Resource Changes: none"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("-------------- Resource Changes --------------\n")
+	groups := groupChanges(changes)
+	for _, action := range cloud.ChangeActions {
+		group := groups[action]
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n%s (%d)\n", changeActionTitles[action], len(group))
+
+		tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		module := ""
+		for i, change := range group {
+			if i == 0 || moduleName(change) != module {
+				module = moduleName(change)
+				fmt.Fprintf(tw, "  %s\n", module)
+			}
+			attrs := make([]string, 0, len(change.Attributes))
+			for _, attr := range change.Attributes {
+				attrs = append(attrs, formatAttributeChange(attr, "%s: %s -> %s"))
+			}
+			fmt.Fprintf(tw, "    %s\t%s\t%s\t%s\n", change.Address, change.Type, change.Provider, strings.Join(attrs, ", "))
+		}
+		tw.Flush()
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// renders changes as markdown tables, one table for each action
+func changeTableMarkdown(changes []*cloud.ResourceChange) string {
+	var b strings.Builder
+	groups := groupChanges(changes)
+	for _, action := range cloud.ChangeActions {
+		group := groups[action]
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n**%s** (%d)\n\n", changeActionTitles[action], len(group))
+		b.WriteString("| Module | Address | Type | Provider | Changed Attributes |\n| --- | --- | --- | --- | --- |\n")
+		for _, change := range group {
+			attrs := make([]string, 0, len(change.Attributes))
+			for _, attr := range change.Attributes {
+				attrs = append(attrs, escapeTableCell(formatAttributeChange(attr, "`%s`: `%s` → `%s`")))
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` | `%s` | %s |\n", moduleName(change), change.Address, change.Type, change.Provider, strings.Join(attrs, "<br>"))
+		}
+	}
+	return b.String()
+}
+
+// keeps attribute values from breaking table rows
+func escapeTableCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+const testResourceChangesPlan = `{
+  "resource_changes": [
+    {
+      "address": "aws_db_instance.main",
+      "type": "aws_db_instance",
+      "name": "main",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {
+        "actions": ["delete", "create"],
+        "before": {"engine_version": "13.4", "password": "old"},
+        "after": {"engine_version": "15.2", "password": "new"},
+        "before_sensitive": {"password": true},
+        "after_sensitive": {"password": true},
+        "replace_paths": [["engine_version"]]
+      }
+    },
+    {
+      "address": "module.network.aws_subnet.a",
+      "module_address": "module.network",
+      "type": "aws_subnet",
+      "name": "a",
+      "provider_name": "registry.terraform.io/hashicorp/aws",
+      "change": {"actions": ["create"], "before": null, "after": {"cidr_block": "10.0.1.0/24"}}
+    }
+  ]
+}`
+
+func TestOutputPlanCommand_ResourceChanges(t *testing.T) {
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	stub := &planStub{planJSON: testResourceChangesPlan}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.PlanService = stub
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+	c := &OutputPlanCommand{Meta: meta}
+	if code := c.Run([]string{"-plan", "plan-123", "-resource-changes"}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	if !stub.options.ShowSensitive {
+		t.Error("expected unredacted JSON execution plan to resolve changes of sensitive attributes")
+	}
+	if strings.Contains(ui.OutputWriter.String(), `"new"`) || strings.Contains(ui.OutputWriter.String(), `"old"`) {
+		t.Errorf("expected sensitive values to be masked, but received:\n%s", ui.OutputWriter.String())
+	}
+	for _, expected := range []string{
+		"Create (1)",
+		"Replace (1)",
+		"module.network.aws_subnet.a",
+		`engine_version: "13.4" -> "15.2" (forces replacement)`,
+		"password: (sensitive value) -> (sensitive value)",
+	} {
+		if !strings.Contains(ui.OutputWriter.String(), expected) {
+			t.Errorf("expected change table to contain %q, but received:\n%s", expected, ui.OutputWriter.String())
+		}
+	}
+
+	v := meta.messages["resource_changes"]
+	if v == nil {
+		t.Fatal("expected resource_changes output")
+	}
+	if changes, ok := v.value.([]*cloud.ResourceChange); !ok || len(changes) != 2 || changes[1].Action != cloud.ChangeReplace {
+		t.Errorf("unexpected resource_changes output: %+v", v.value)
+	}
+	if !v.stdOut || !v.platformOut {
+		t.Errorf("expected resource_changes in the json result and platform output, but received: %+v", v)
+	}
+}
+
+func TestChangeTableMarkdown(t *testing.T) {
+	changes := []*cloud.ResourceChange{
+		{Address: "module.network.aws_subnet.a", Module: "module.network", Type: "aws_subnet", Provider: "hashicorp/aws", Action: cloud.ChangeCreate},
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Provider: "hashicorp/aws", Action: cloud.ChangeReplace, Attributes: []*cloud.AttributeChange{
+			{Name: "engine_version", Before: `"13.4"`, After: `"15.2"`, ForcesReplacement: true},
+			{Name: "tags", Before: `{"a":"x|y"}`, After: `{}`},
+		}},
+	}
+
+	expected := strings.Join([]string{
+		"",
+		"**Create** (1)",
+		"",
+		"| Module | Address | Type | Provider | Changed Attributes |",
+		"| --- | --- | --- | --- | --- |",
+		"| module.network | `module.network.aws_subnet.a` | `aws_subnet` | `hashicorp/aws` |  |",
+		"",
+		"**Replace** (1)",
+		"",
+		"| Module | Address | Type | Provider | Changed Attributes |",
+		"| --- | --- | --- | --- | --- |",
+		"| (root) | `aws_db_instance.main` | `aws_db_instance` | `hashicorp/aws` | `engine_version`: `\"13.4\"` → `\"15.2\"` (forces replacement)<br>`tags`: `{\"a\":\"x\\|y\"}` → `{}` |",
+		"",
+	}, "\n")
+	if actual := changeTableMarkdown(changes); actual != expected {
+		t.Errorf("expected:\n%s\nbut received:\n%s", expected, actual)
+	}
+
+	summary := (&runSummary{title: "HCP Terraform Run", changes: changes}).markdown()
+	if !strings.Contains(summary, "#### Resource Changes\n\n**Create** (1)") {
+		t.Errorf("expected summary to contain resource changes, but received:\n%s", summary)
+	}
+}
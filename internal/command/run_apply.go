@@ -15,14 +15,41 @@ import (
 type ApplyRunCommand struct {
 	*Meta
 
-	RunID   string
-	Comment string
+	RunID     string
+	Comment   string
+	Directory string
+	// report run as a GitHub check on the commit
+	GitHubCheck bool
+	// GitHub environment the apply is reported as a deployment to
+	GitHubDeployment string
+	// print the apply log while the run is in progress
+	StreamLogs bool
+	// how structured apply log lines are printed
+	LogView     string
+	HideRefresh bool
+	// safeguards checked against the plan before the run is confirmed
+	MaxDestroy       int
+	MaxChanges       int
+	DenyReplace      []string
+	AllowDestructive bool
+	Justification    string
 }
 
 func (c *ApplyRunCommand) flags() *flag.FlagSet {
 	f := c.flagSet("run apply")
 	f.StringVar(&c.RunID, "run", "", "Existing HCP Terraform Run ID to Apply.")
-	f.StringVar(&c.Comment, "comment", "", "An optional comment about the run.")
+	f.StringVar(&c.Comment, "comment", "", "An optional comment about the run. Supports the same Go text/template fields as -message-template of run create.")
+	f.BoolVar(&c.GitHubCheck, "github-check", false, "Reports the apply as a check on the triggering commit when running in GitHub Actions. Requires `GITHUB_TOKEN` with the `checks: write` permission.")
+	f.StringVar(&c.GitHubDeployment, "github-deployment", "", "Reports the apply as a GitHub deployment of the triggering commit to the given environment. Requires `GITHUB_TOKEN`.")
+	f.StringVar(&c.LogView, "log-view", string(cloud.LogViewCompact), "How structured apply log lines are printed: compact, changes, errors or raw.")
+	f.BoolVar(&c.HideRefresh, "hide-refresh", false, "Omits refresh progress of resources and data sources from the apply log.")
+	f.BoolVar(&c.StreamLogs, "stream-logs", true, "Prints the apply log as it is written while the apply is in progress, otherwise the log is read once the apply completed.")
+	f.StringVar(&c.Directory, "directory", "", "Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the apply.")
+	f.IntVar(&c.MaxDestroy, "max-destroy", -1, "Blocks the apply when the plan destroys more resources, replacements included.")
+	f.IntVar(&c.MaxChanges, "max-changes", -1, "Blocks the apply when the plan adds, changes, destroys and imports more resources in total.")
+	f.Var((*flagStringSlice)(&c.DenyReplace), "deny-replace", "Blocks the apply when the plan replaces a resource of a matching type, eg. aws_db_*. You can use this option multiple times.")
+	f.BoolVar(&c.AllowDestructive, "allow-destructive", false, "Applies the run although safeguards are violated. Requires -justification.")
+	f.StringVar(&c.Justification, "justification", "", "Why safeguards are overridden with -allow-destructive, posted as a run comment. Supports the same Go text/template fields as -comment.")
 
 	return f
 }
@@ -39,6 +66,20 @@ func (c *ApplyRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.useLogOptions(c.LogView, c.HideRefresh); err != nil {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult(err.Error())
+		return 1
+	}
+
+	if c.AllowDestructive && strings.TrimSpace(c.Justification) == "" {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult("overriding safeguards with -allow-destructive requires a -justification")
+		return 1
+	}
+
 	// fetch existing run details
 	run, runErr := c.cloud.GetRun(c.appCtx, cloud.GetRunOptions{
 		RunID: c.RunID,
@@ -67,13 +108,39 @@ func (c *ApplyRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	comment, commentErr := c.renderRunComment(c.Comment, run)
+	if commentErr != nil {
+		c.addOutput("status", string(Error))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(commentErr.Error())
+		c.writer.OutputResult(c.closeOutput())
+		return 1
+	}
+
+	if code, blocked := c.checkSafeguards(run); blocked {
+		return code
+	}
+
+	check := c.newRunCheck(c.GitHubCheck, fmt.Sprintf("HCP Terraform Apply / %s", c.RunID))
+	deployment := c.newRunDeployment(c.GitHubDeployment)
+	applyLogTail := c.newApplyLogTail(c.StreamLogs)
+
 	latestRun, applyError := c.cloud.ApplyRun(c.appCtx, cloud.ApplyRunOptions{
 		RunID:   c.RunID,
-		Comment: c.Comment,
+		Comment: comment,
+		OnStatusChange: func(r *tfe.Run) {
+			check.update(r)
+			deployment.update(r)
+			applyLogTail.update(r)
+		},
 	})
 	if latestRun != nil {
 		run = latestRun
-		c.readApplyLogs(run)
+	}
+	check.complete(run, applyError)
+	deployment.complete(run, applyError)
+	if latestRun != nil {
+		c.readApplyLogs(run, applyLogTail, applyError, c.Directory)
 	}
 
 	if applyError != nil {
@@ -91,25 +158,74 @@ func (c *ApplyRunCommand) Run(args []string) int {
 	return 0
 }
 
+// checks the plan against the safeguards, returns whether the command ended with the exit code instead of applying.
+// Violations are overridden with -allow-destructive once the justification is posted as a run comment.
+func (c *ApplyRunCommand) checkSafeguards(run *tfe.Run) (int, bool) {
+	guard := &applyGuard{
+		maxDestroy:  c.MaxDestroy,
+		maxChanges:  c.MaxChanges,
+		denyReplace: c.DenyReplace,
+	}
+	violations, err := c.applyGuardViolations(guard, run)
+	if err != nil {
+		c.addOutput("status", string(Error))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(fmt.Sprintf("unable to check safeguards of run %s: %s", c.RunID, err.Error()))
+		c.writer.OutputResult(c.closeOutput())
+		return 1, true
+	}
+	if len(violations) == 0 {
+		return 0, false
+	}
+
+	c.addOutputWithOpts("violations", violations, &outputOpts{
+		stdOut:      true,
+		multiLine:   true,
+		platformOut: true,
+	})
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, fmt.Sprintf("  [%s] %s", v.Rule, v.Message))
+	}
+
+	if !c.AllowDestructive {
+		c.addOutput("status", string(Blocked))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(fmt.Sprintf("run %s was not applied, the plan violates safeguards:\n%s\nReview the plan and rerun with -allow-destructive and a -justification to apply it anyway.", c.RunID, strings.Join(messages, "\n")))
+		c.writer.OutputResult(c.closeOutput())
+		return violationExitCode, true
+	}
+
+	justification, err := c.renderRunComment(c.Justification, run)
+	if err == nil {
+		err = c.cloud.CommentRun(c.appCtx, cloud.CommentRunOptions{
+			RunID: c.RunID,
+			Body:  applyOverrideComment(justification, violations),
+		})
+	}
+	if err != nil {
+		c.addOutput("status", string(Error))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(fmt.Sprintf("run %s was not applied, unable to post the justification for overriding safeguards: %s", c.RunID, err.Error()))
+		c.writer.OutputResult(c.closeOutput())
+		return 1, true
+	}
+	c.writer.Output(fmt.Sprintf("Safeguards overridden with -allow-destructive:\n%s", strings.Join(messages, "\n")))
+	return 0, false
+}
+
 func (c *ApplyRunCommand) addRunDetails(run *tfe.Run) {
 	if run == nil {
 		return
 	}
-	link, _ := c.cloud.RunLink(c.appCtx, c.organization, run)
+	link := c.runLink(run)
 	if link != "" {
 		c.addOutput("run_link", link)
 	}
 	c.addOutput("run_id", run.ID)
 	c.addOutput("run_status", string(run.Status))
-}
 
-func (c *ApplyRunCommand) readApplyLogs(run *tfe.Run) {
-	// pre-apply task stage
-	c.cloud.LogTaskStage(c.appCtx, run, tfe.PreApply)
-	// apply logs
-	if logErr := c.cloud.GetApplyLogs(c.appCtx, run.Apply.ID); logErr != nil {
-		c.writer.ErrorResult(fmt.Sprintf("failed to read apply logs: %s", logErr.Error()))
-	}
+	c.writeRunSummary("HCP Terraform Apply", run, link)
 }
 
 func (c *ApplyRunCommand) Help() string {
@@ -130,7 +246,35 @@ Options:
 
 	-run         Existing HCP Terraform Run ID to Apply.
 
-	-comment     An optional comment about the run.
+	-comment     An optional comment about the run. Supports the same Go text/template fields as -message-template of run create.
+
+	-github-check        Reports the apply as a check on the triggering commit when running in GitHub Actions. Requires "GITHUB_TOKEN" with the "checks: write" permission.
+
+	-github-deployment  Reports the apply as a GitHub deployment of the triggering commit to the given environment. Requires "GITHUB_TOKEN".
+
+	-log-view    How structured apply log lines are printed: "compact" (default), "changes", "errors" or "raw".
+
+	-hide-refresh  Omits refresh progress of resources and data sources from the apply log.
+
+	-stream-logs=false  Read the apply log once the apply completed, instead of printing it while the apply is in progress.
+
+	-directory   Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the apply.
+
+	-max-destroy  Blocks the apply when the plan destroys more resources, replacements included.
+
+	-max-changes  Blocks the apply when the plan adds, changes, destroys and imports more resources in total.
+
+	-deny-replace  Blocks the apply when the plan replaces a resource of a matching type, eg. "aws_db_*". You can use this option multiple times. Requires admin access to the workspace to read the JSON execution plan.
+
+	-allow-destructive  Applies the run although safeguards are violated. Requires -justification.
+
+	-justification  Why safeguards are overridden with -allow-destructive, posted as a run comment. Supports the same Go text/template fields as -comment.
+
+Exit Codes:
+
+	0   Success, the run was applied or there was nothing to apply
+	1   Error
+	2   Blocked, the plan violates safeguards and the run was not applied
 	`
 	return strings.TrimSpace(helpText)
 }
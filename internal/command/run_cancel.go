@@ -23,7 +23,7 @@ type CancelRunCommand struct {
 func (c *CancelRunCommand) flags() *flag.FlagSet {
 	f := c.flagSet("run cancel")
 	f.StringVar(&c.RunID, "run", "", "Existing HCP Terraform Run ID to Discard.")
-	f.StringVar(&c.Comment, "comment", "", "An optional comment about the run.")
+	f.StringVar(&c.Comment, "comment", "", "An optional comment about the run. Supports the same Go text/template fields as -message-template of run create.")
 	f.BoolVar(&c.ForceCancel, "force-cancel", false, "Ends the run immediately.")
 
 	return f
@@ -69,9 +69,18 @@ func (c *CancelRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	comment, commentErr := c.renderRunComment(c.Comment, run)
+	if commentErr != nil {
+		c.addOutput("status", string(Error))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(commentErr.Error())
+		c.writer.OutputResult(c.closeOutput())
+		return 1
+	}
+
 	latestRun, cancelErr := c.cloud.CancelRun(c.appCtx, cloud.CancelRunOptions{
 		RunID:       c.RunID,
-		Comment:     c.Comment,
+		Comment:     comment,
 		ForceCancel: c.ForceCancel,
 	})
 	if latestRun != nil {
@@ -123,7 +132,7 @@ Options:
 
   -run            Existing HCP Terraform Run ID to Discard.
 
-	-comment        An optional comment about the run.
+	-comment        An optional comment about the run. Supports the same Go text/template fields as -message-template of run create.
 
 	-force-cancel   Ends the run immediately.
 	`
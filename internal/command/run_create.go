@@ -19,12 +19,34 @@ type CreateRunCommand struct {
 	Workspace              string
 	ConfigurationVersionID string
 	Message                string
+	MessageTemplate        string
+	MessageTemplateFile    string
 	TargetAddrs            []string
+	Directory              string
 
 	PlanOnly  bool
 	IsDestroy bool
 	Refresh   bool
 	SavePlan  bool
+	PRComment bool
+	// report run as a GitHub check on the commit
+	GitHubCheck bool
+	// print the plan log while the run is in progress
+	StreamLogs bool
+	// how structured plan log lines are printed
+	LogView     string
+	HideRefresh bool
+	// render a table of resource changes from the JSON execution plan
+	ResourceChanges bool
+	// wait for the run to complete, otherwise return once it is created
+	Wait bool
+	// phase of the run lifecycle waited for, defaults to a phase inferred from the run
+	WaitUntil string
+	// discard or cancel older runs of the same branch or pull request
+	Supersede bool
+
+	// details parsed from the plan log
+	planLog *cloud.RunLog
 }
 
 // flagStringSlice is a flag.Value implementation which allows collecting
@@ -53,10 +75,22 @@ func (c *CreateRunCommand) flags() *flag.FlagSet {
 	f.StringVar(&c.Workspace, "workspace", "", "The name of the HCP Terraform Workspace.")
 	f.StringVar(&c.ConfigurationVersionID, "configuration_version", "", "The Configuration Version ID to use for this run.")
 	f.StringVar(&c.Message, "message", "", "Specifies the message to be associated with this run. A default message will be set.")
+	f.StringVar(&c.MessageTemplate, "message-template", "", "Go text/template for the run message, used when -message is not set. Has access to the CI context, workspace and configuration version.")
+	f.StringVar(&c.MessageTemplateFile, "message-template-file", "", "Path of a file containing the -message-template.")
 	f.BoolVar(&c.PlanOnly, "plan-only", false, "Specifies if this is a HCP Terraform speculative, plan-only run that cannot be applied.")
 	f.BoolVar(&c.IsDestroy, "is-destroy", false, "Specifies that the plan is a destroy plan. When true, the plan destroys all provisioned resources.")
 	f.BoolVar(&c.SavePlan, "save-plan", false, "Specifies whether to create a saved plan. Saved-plan runs perform their plan and checks immediately, but won't lock the workspace and become its current run until they are confirmed for apply.")
 	f.BoolVar(&c.Refresh, "refresh", true, "When this value is false, skip checking for external changes to remote objects while creating the plan. This can potentially make planning faster, but at the expense of possibly planning against a stale record of the remote system state.")
+	f.StringVar(&c.Directory, "directory", "", "Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the plan.")
+	f.BoolVar(&c.PRComment, "pr-comment", false, "Creates or updates a pull request comment with the run summary. Requires `GITHUB_TOKEN` on GitHub Actions, `GITLAB_TOKEN` set to a project or personal access token on GitLab merge request pipelines.")
+	f.BoolVar(&c.GitHubCheck, "github-check", false, "Reports the run as a check on the triggering commit when running in GitHub Actions. Requires `GITHUB_TOKEN` with the `checks: write` permission.")
+	f.StringVar(&c.LogView, "log-view", string(cloud.LogViewCompact), "How structured plan log lines are printed: compact, changes, errors or raw.")
+	f.BoolVar(&c.HideRefresh, "hide-refresh", false, "Omits refresh progress of resources and data sources from the plan log.")
+	f.BoolVar(&c.ResourceChanges, "resource-changes", false, "Prints a table of resource changes from the JSON execution plan once the plan finished and adds it to summaries, pull request comments and the `resource_changes` output. Sensitive values are masked.")
+	f.BoolVar(&c.StreamLogs, "stream-logs", true, "Prints the plan log as it is written while the run is in progress, otherwise the log is read once the run completed.")
+	f.BoolVar(&c.Wait, "wait", true, "Waits for the run to complete. When false, returns once the run is created with its `run_id` and `run_link`, use `run wait` to wait for it later.")
+	f.StringVar(&c.WaitUntil, "wait-until", "", "Phase the run is waited for: planned, post_plan_completed, cost_estimated, policy_checked, confirmable, applied or completed. Defaults to completed for plan-only runs, applied for auto-apply runs, otherwise the last enabled check or confirmable.")
+	f.BoolVar(&c.Supersede, "supersede", false, "Discards or cancels older pending or planned runs that tfci created on the workspace for the same pull request or branch, before waiting for the new run. The run message is tagged to find the runs.")
 	f.Var((*flagStringSlice)(&c.TargetAddrs), "target", "Limit the planning operation to only the given module, resource, or resource instance and all of its dependencies. You can use this option multiple times to include more than one object. This is for exceptional use only. e.g. -target=aws_s3_bucket.foo")
 	return f
 }
@@ -66,11 +100,58 @@ func (c *CreateRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	if err := c.useLogOptions(c.LogView, c.HideRefresh); err != nil {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult(err.Error())
+		return 1
+	}
+
+	var waitUntil cloud.RunPhase
+	if c.WaitUntil != "" {
+		phase, err := cloud.ParseRunPhase(c.WaitUntil)
+		if err != nil {
+			c.addOutput("status", string(Error))
+			c.closeOutput()
+			c.writer.ErrorResult(err.Error())
+			return 1
+		}
+		waitUntil = phase
+	}
+
 	runVars := collectVariables()
 
 	// default formatted message for run, include vcs ci runner information
 	if c.Message == "" {
-		c.Message = c.defaultRunMessage()
+		message, err := c.runMessage()
+		if err != nil {
+			c.addOutput("status", string(Error))
+			c.closeOutput()
+			c.writer.ErrorResult(err.Error())
+			return 1
+		}
+		c.Message = message
+	}
+
+	var supersedeTag, supersedeTarget string
+	if c.Supersede {
+		tag, target, err := c.supersedeTag()
+		if err != nil {
+			c.addOutput("status", string(Error))
+			c.closeOutput()
+			c.writer.ErrorResult(err.Error())
+			return 1
+		}
+		supersedeTag, supersedeTarget = tag, target
+		c.Message = tagRunMessage(c.Message, tag)
+	}
+
+	// runs that are not waited for are reported by run wait
+	check := c.newRunCheck(c.GitHubCheck && c.Wait, fmt.Sprintf("HCP Terraform / %s", c.Workspace))
+	planLogTail := c.newPlanLogTail(c.StreamLogs && c.Wait)
+	onStatusChange := func(r *tfe.Run) {
+		check.update(r)
+		planLogTail.update(r)
 	}
 
 	run, runError := c.cloud.CreateRun(c.appCtx, cloud.CreateRunOptions{
@@ -84,9 +165,24 @@ func (c *CreateRunCommand) Run(args []string) int {
 		SavePlan:               c.SavePlan,
 		RunVariables:           runVars,
 		TargetAddrs:            c.TargetAddrs,
+		NoWait:                 !c.Wait || c.Supersede,
+		WaitUntil:              waitUntil,
+		OnStatusChange:         onStatusChange,
 	})
-	if run != nil {
-		c.readPlanLogs(run)
+	// older runs are superseded before waiting for the new run
+	if runError == nil && c.Supersede {
+		c.supersedeRuns(run, supersedeTag, supersedeTarget)
+		if c.Wait {
+			run, runError = c.cloud.WaitRun(c.appCtx, cloud.WaitRunOptions{
+				RunID:          run.ID,
+				Until:          waitUntil,
+				OnStatusChange: onStatusChange,
+			})
+		}
+	}
+	check.complete(run, runError)
+	if run != nil && c.Wait {
+		c.planLog = c.readPlanLogs(run, planLogTail, runError, c.Directory)
 	}
 
 	if runError != nil {
@@ -110,55 +206,32 @@ func (c *CreateRunCommand) addRunDetails(run *tfe.Run) {
 		log.Printf("[ERROR] run is not detected")
 		return
 	}
-	runLink, _ := c.cloud.RunService.RunLink(c.appCtx, c.organization, run)
-	if runLink != "" {
-		c.addOutput("run_link", runLink)
-	}
-	c.addOutput("run_id", run.ID)
-	c.addOutput("run_status", string(run.Status))
-	c.addOutput("run_message", run.Message)
-	c.addOutput("plan_id", run.Plan.ID)
-	c.addOutput("plan_status", string(run.Plan.Status))
-	c.addOutput("configuration_version_id", run.ConfigurationVersion.ID)
-
-	// add cost estimation info if enabled on run
-	if run.CostEstimate != nil {
-		c.addOutput("cost_estimation_id", run.CostEstimate.ID)
-		c.addOutput("cost_estimation_status", string(run.CostEstimate.Status))
-		if run.CostEstimate.ErrorMessage != "" {
-			c.writer.ErrorResult(fmt.Sprintf("Cost Estimation errored: %s", run.CostEstimate.ErrorMessage))
-		}
+	// the run has only been created, its plan is reported by run wait
+	if !c.Wait {
+		c.addRunOutputs(run, c.runLink(run))
+		return
 	}
-
-	c.addOutputWithOpts("payload", run, &outputOpts{
-		stdOut:      false,
-		multiLine:   true,
-		platformOut: true,
+	c.addCreatedRunDetails(run, &createdRunOpts{
+		workspace:       c.Workspace,
+		planLog:         c.planLog,
+		resourceChanges: c.ResourceChanges,
+		prComment:       c.PRComment,
 	})
 }
 
-func (c *CreateRunCommand) readPlanLogs(run *tfe.Run) {
-	// Pre Plan task stages
-	c.cloud.LogTaskStage(c.appCtx, run, tfe.PrePlan)
-	// Plan
-	if pLogErr := c.cloud.GetPlanLogs(c.appCtx, run.Plan.ID); pLogErr != nil {
-		c.writer.ErrorResult(fmt.Sprintf("failed to read plan logs: %s", pLogErr.Error()))
+// renders the message template, falls back to the default message including vcs ci runner information
+func (c *CreateRunCommand) runMessage() (string, error) {
+	tmpl, err := readMessageTemplate(c.MessageTemplate, c.MessageTemplateFile)
+	if err != nil {
+		return "", err
 	}
-	// Post Plan task stages
-	c.cloud.LogTaskStage(c.appCtx, run, tfe.PostPlan)
-	// cost estimation
-	c.cloud.LogCostEstimation(c.appCtx, run)
-	// sentinel policies
-	if policyLogErr := c.cloud.GetPolicyCheckLogs(c.appCtx, run); policyLogErr != nil {
-		c.writer.ErrorResult(fmt.Sprintf("failed to read policy check logs: %s", policyLogErr.Error()))
-	}
-}
-
-func (c *CreateRunCommand) defaultRunMessage() string {
-	if c.env.Context != nil {
-		return fmt.Sprintf("Triggered from HCP Terraform CI by Author (%s) for SHA (%s)", c.env.Context.Author(), c.env.Context.SHAShort())
+	if tmpl == "" {
+		if c.env == nil || c.env.Context == nil {
+			return defaultRunMessage, nil
+		}
+		tmpl = defaultRunMessageTemplate
 	}
-	return `Triggered from HCP Terraform CI`
+	return renderMessage(tmpl, c.newMessageData(c.Workspace, c.ConfigurationVersionID, ""))
 }
 
 func (c *CreateRunCommand) Help() string {
@@ -183,11 +256,25 @@ Options:
 
 	-message                Specifies the message to be associated with this run. A default message will be set.
 
+	-message-template       Go text/template for the run message, used when -message is not set. Fields: {{.SHA}}, {{.SHAShort}}, {{.Author}}, {{.Ref}}, {{.PullRequest}}, {{.PullRequestURL}}, {{.JobURL}}, {{.Repository}}, {{.Platform}}, {{.Workspace}} and {{.ConfigurationVersionID}}.
+
+	-message-template-file  Path of a file containing the -message-template.
+
 	-plan-only              Specifies if this is a HCP Terraform speculative, plan-only run that cannot be applied.
 
 	-refresh=false          Skip checking for external changes to remote objects while creating the plan. This can potentially make planning faster, but at the expense of possibly planning against a stale record of the remote system state.
 	-save-plan              Specifies whether to create a saved plan. Saved-plan runs perform their plan and checks immediately, but won't lock the workspace and become its current run until they are confirmed for apply.
 	-is-destroy				Specifies whether to create a destroy run.
+	-directory              Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the plan.
+	-pr-comment             Creates or updates a pull request comment with the run summary. Requires "GITHUB_TOKEN" on GitHub Actions, "GITLAB_TOKEN" set to a project or personal access token on GitLab merge request pipelines.
+	-github-check           Reports the run as a check on the triggering commit when running in GitHub Actions. Requires "GITHUB_TOKEN" with the "checks: write" permission.
+	-log-view               How structured plan log lines are printed: "compact" (default) prints messages like the Terraform CLI, "changes" only resource changes, outputs and errors, "errors" only errors, "raw" the JSON lines as received.
+	-hide-refresh           Omits refresh progress of resources and data sources from the plan log.
+	-resource-changes       Prints a table of resource changes from the JSON execution plan and adds it to summaries, pull request comments and the "resource_changes" output. Sensitive values are masked.
+	-stream-logs=false      Read the plan log once the run completed, instead of printing it while the run is in progress.
+	-wait-until             Phase of the run lifecycle the run is waited for: "planned", "post_plan_completed", "cost_estimated", "policy_checked", "confirmable", "applied" or "completed". Defaults to "completed" for plan-only runs, "applied" for auto-apply runs, otherwise "policy_checked" or "cost_estimated" when enabled, or "confirmable".
+	-supersede              Discards or cancels older pending or planned runs that tfci created on the workspace for the same pull request or branch, with a comment naming the new run. The run message is tagged with the repository and pull request or branch, eg. "[tfci:github.com/org/repo#12]".
+	-wait=false             Returns once the run is created with its "run_id" and "run_link", instead of waiting for the run to complete. Use "run wait" to wait for the run later.
 	-target					Focuses Terraform's attention on only a subset of resources and their dependencies. This option accepts multiple instances by providing additional target option flags.
 	`
 	return strings.TrimSpace(helpText)
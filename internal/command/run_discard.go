@@ -22,7 +22,7 @@ type DiscardRunCommand struct {
 func (c *DiscardRunCommand) flags() *flag.FlagSet {
 	f := c.flagSet("run discard")
 	f.StringVar(&c.RunID, "run", "", "HCP Terraform Run ID to Discard")
-	f.StringVar(&c.Comment, "comment", "", "An optional comment about the run.")
+	f.StringVar(&c.Comment, "comment", "", "An optional comment about the run. Supports the same Go text/template fields as -message-template of run create.")
 
 	return f
 }
@@ -59,9 +59,18 @@ func (c *DiscardRunCommand) Run(args []string) int {
 		return 1
 	}
 
+	comment, commentErr := c.renderRunComment(c.Comment, run)
+	if commentErr != nil {
+		c.addOutput("status", string(Error))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(commentErr.Error())
+		c.writer.OutputResult(c.closeOutput())
+		return 1
+	}
+
 	latestRun, discardErr := c.cloud.DiscardRun(c.appCtx, cloud.DiscardRunOptions{
 		RunID:   c.RunID,
-		Comment: c.Comment,
+		Comment: comment,
 	})
 	// update latest run results
 	if latestRun != nil {
@@ -113,7 +122,7 @@ Options:
 
 	-run         Existing HCP Terraform Run ID to Discard.
 
-	-comment     An optional comment about the run.
+	-comment     An optional comment about the run. Supports the same Go text/template fields as -message-template of run create.
 	`
 	return strings.TrimSpace(helpText)
 }
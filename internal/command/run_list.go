@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// messages longer than this many characters are truncated in the run table
+const maxRunMessageLength = 60
+
+// operation filter names, mapped to operations of the Runs API
+var runOperationNames = map[string]tfe.RunOperation{
+	"plan-and-apply": tfe.RunOperationPlanApply,
+	"plan-only":      tfe.RunOperationPlanOnly,
+	"refresh":        tfe.RunOperationRefreshOnly,
+	"refresh-only":   tfe.RunOperationRefreshOnly,
+	"destroy":        tfe.RunOperationDestroy,
+	"empty-apply":    tfe.RunOperationEmptyApply,
+	"save-plan":      tfe.RunOperationSavePlan,
+}
+
+type ListRunsCommand struct {
+	*Meta
+
+	Workspace     string
+	Statuses      []string
+	Operations    []string
+	Sources       []string
+	Commit        string
+	Message       string
+	CreatedAfter  string
+	CreatedBefore string
+	Limit         int
+}
+
+// RunListItem is a listed run
+type RunListItem struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Operation string    `json:"operation"`
+	Source    string    `json:"source"`
+	Commit    string    `json:"commit,omitempty"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (c *ListRunsCommand) flags() *flag.FlagSet {
+	f := c.flagSet("run list")
+	f.StringVar(&c.Workspace, "workspace", "", "The name of the HCP Terraform Workspace.")
+	f.Var((*flagStringSlice)(&c.Statuses), "status", "Lists runs with the status, eg. applied or planned_and_finished. Accepts a comma-separated list and can be set multiple times.")
+	f.Var((*flagStringSlice)(&c.Operations), "operation", "Lists runs with the operation: plan-and-apply, plan-only, refresh, destroy, empty-apply or save-plan. Accepts a comma-separated list and can be set multiple times.")
+	f.Var((*flagStringSlice)(&c.Sources), "source", "Lists runs with the source, eg. tfe-api, tfe-configuration-version or tfe-ui. Accepts a comma-separated list and can be set multiple times.")
+	f.StringVar(&c.Commit, "commit", "", "Lists runs of the commit SHA, matching the commit of VCS-triggered runs or the run message of runs created by tfci.")
+	f.StringVar(&c.Message, "message", "", "Lists runs with a message containing the text, case-insensitive.")
+	f.StringVar(&c.CreatedAfter, "created-after", "", "Lists runs created after the time, an RFC 3339 timestamp or a duration before now, eg. 24h.")
+	f.StringVar(&c.CreatedBefore, "created-before", "", "Lists runs created before the time, an RFC 3339 timestamp or a duration before now, eg. 1h.")
+	f.IntVar(&c.Limit, "limit", 20, "Maximum number of runs listed, newest first.")
+	return f
+}
+
+func (c *ListRunsCommand) Run(args []string) int {
+	if err := c.setupCmd(args, c.flags()); err != nil {
+		return 1
+	}
+
+	if c.Workspace == "" {
+		return c.listError("listing runs requires a workspace name (use --workspace)")
+	}
+
+	options, err := c.listOptions(time.Now())
+	if err != nil {
+		return c.listError(err.Error())
+	}
+
+	runs, err := c.cloud.ListRuns(c.appCtx, options)
+	if err != nil {
+		c.addOutput("status", string(c.resolveStatus(err)))
+		c.closeOutput()
+		c.writer.ErrorResult(fmt.Sprintf("error listing runs of workspace %s: %s", c.Workspace, err.Error()))
+		return 1
+	}
+
+	items := make([]*RunListItem, 0, len(runs))
+	for _, run := range runs {
+		items = append(items, &RunListItem{
+			ID:        run.ID,
+			Status:    string(run.Status),
+			Operation: string(cloud.RunOperation(run)),
+			Source:    string(run.Source),
+			Commit:    cloud.RunCommit(run),
+			Message:   run.Message,
+			CreatedAt: run.CreatedAt,
+		})
+	}
+
+	c.writer.Output(runTable(items))
+	c.addOutput("run_count", fmt.Sprint(len(items)))
+	// the newest matching run, eg. to apply or inspect it in a later step
+	if len(items) > 0 {
+		c.addOutput("run_id", items[0].ID)
+	}
+	c.addOutputWithOpts("runs", items, &outputOpts{
+		stdOut:      true,
+		multiLine:   true,
+		platformOut: true,
+	})
+	c.addOutput("status", string(Success))
+	c.writer.OutputResult(c.closeOutput())
+	return 0
+}
+
+func (c *ListRunsCommand) listError(msg string) int {
+	c.addOutput("status", string(Error))
+	c.closeOutput()
+	c.writer.ErrorResult(msg)
+	return 1
+}
+
+func (c *ListRunsCommand) listOptions(now time.Time) (cloud.ListRunsOptions, error) {
+	options := cloud.ListRunsOptions{
+		Organization: c.organization,
+		Workspace:    c.Workspace,
+		Statuses:     c.Statuses,
+		Sources:      c.Sources,
+		Commit:       c.Commit,
+		Message:      c.Message,
+		Limit:        c.Limit,
+	}
+	for _, name := range c.Operations {
+		operation, ok := runOperationNames[strings.ReplaceAll(name, "_", "-")]
+		if !ok {
+			return options, fmt.Errorf("unsupported run operation: %q", name)
+		}
+		options.Operations = append(options.Operations, string(operation))
+	}
+
+	var err error
+	if options.CreatedAfter, err = parseTimeFlag(c.CreatedAfter, now); err != nil {
+		return options, fmt.Errorf("invalid -created-after: %w", err)
+	}
+	if options.CreatedBefore, err = parseTimeFlag(c.CreatedBefore, now); err != nil {
+		return options, fmt.Errorf("invalid -created-before: %w", err)
+	}
+	return options, nil
+}
+
+// parses an RFC 3339 timestamp or a duration before now, empty values return the zero time
+func parseTimeFlag(v string, now time.Time) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or a duration, received: %q", v)
+	}
+	return now.Add(-d), nil
+}
+
+func runTable(items []*RunListItem) string {
+	if len(items) == 0 {
+		return "No runs found"
+	}
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN ID\tSTATUS\tOPERATION\tSOURCE\tCOMMIT\tCREATED\tMESSAGE")
+	for _, item := range items {
+		commit := item.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		message := strings.ReplaceAll(item.Message, "\n", " ")
+		if runes := []rune(message); len(runes) > maxRunMessageLength {
+			message = string(runes[:maxRunMessageLength-3]) + "..."
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", item.ID, item.Status, item.Operation, item.Source, commit, item.CreatedAt.UTC().Format(time.RFC3339), message)
+	}
+	tw.Flush()
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func (c *ListRunsCommand) Help() string {
+	helpText := `
+Usage: tfci [global options] run list [options]
+
+	Lists runs of a workspace newest first, filtered by status, operation, source, commit, message and creation time.
+
+Global Options:
+
+	-hostname       The hostname of a Terraform Enterprise installation, if using Terraform Enterprise. Defaults to "app.terraform.io".
+
+	-token          The token used to authenticate with HCP Terraform. Defaults to reading "TF_API_TOKEN" environment variable.
+
+	-organization   HCP Terraform Organization Name.
+
+Options:
+
+	-workspace       The name of the HCP Terraform Workspace.
+
+	-status          Lists runs with the status, eg. "applied" or "planned_and_finished". Accepts a comma-separated list and can be set multiple times.
+
+	-operation       Lists runs with the operation: "plan-and-apply", "plan-only", "refresh", "destroy", "empty-apply" or "save-plan".
+
+	-source          Lists runs with the source, eg. "tfe-api", "tfe-configuration-version" or "tfe-ui".
+
+	-commit          Lists runs of the commit SHA, matching the commit of VCS-triggered runs or the run message of runs created by tfci.
+
+	-message         Lists runs with a message containing the text, case-insensitive.
+
+	-created-after   Lists runs created after the time, an RFC 3339 timestamp or a duration before now, eg. "24h".
+
+	-created-before  Lists runs created before the time, an RFC 3339 timestamp or a duration before now, eg. "1h".
+
+	-limit           Maximum number of runs listed, newest first. Defaults to 20.
+	`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ListRunsCommand) Synopsis() string {
+	return "Lists runs of a workspace"
+}
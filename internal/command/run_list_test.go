@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// returns listed runs and records the list options
+type listRunsStub struct {
+	cloud.RunService
+	options cloud.ListRunsOptions
+	runs    []*tfe.Run
+}
+
+func (s *listRunsStub) ListRuns(_ context.Context, options cloud.ListRunsOptions) ([]*tfe.Run, error) {
+	s.options = options
+	return s.runs, nil
+}
+
+func TestListRunsCommand(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	stub := &listRunsStub{runs: []*tfe.Run{
+		{ID: "run-2", Status: tfe.RunPlannedAndFinished, PlanOnly: true, Source: tfe.RunSourceAPI, Message: "Triggered from HCP Terraform CI", CreatedAt: created},
+		{ID: "run-1", Status: tfe.RunApplied, Source: tfe.RunSourceConfigurationVersion, CreatedAt: created.Add(-time.Hour), ConfigurationVersion: &tfe.ConfigurationVersion{IngressAttributes: &tfe.IngressAttributes{CommitSHA: "abc1234def"}}},
+	}}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = stub
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+	c := &ListRunsCommand{Meta: meta}
+	args := []string{"-workspace", "ws", "-status", "planned_and_finished,applied", "-operation", "plan-only", "-operation", "destroy", "-created-after", "2024-04-01T00:00:00Z", "-limit", "5"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	options := stub.options
+	if options.Organization != "org" || options.Workspace != "ws" || options.Limit != 5 {
+		t.Errorf("unexpected list options: %+v", options)
+	}
+	if strings.Join(options.Statuses, ",") != "planned_and_finished,applied" || strings.Join(options.Operations, ",") != "plan_only,destroy" {
+		t.Errorf("unexpected filters: %+v", options)
+	}
+	if !options.CreatedAfter.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected created after: %s", options.CreatedAfter)
+	}
+
+	if v := meta.messages["run_id"]; v == nil || v.value != "run-2" {
+		t.Errorf("expected newest run_id run-2, but received: %+v", v)
+	}
+	if v := meta.messages["run_count"]; v == nil || v.value != "2" {
+		t.Errorf("expected run_count 2, but received: %+v", v)
+	}
+	items, ok := meta.messages["runs"].value.([]*RunListItem)
+	if !ok || len(items) != 2 || items[0].Operation != "plan_only" || items[1].Commit != "abc1234def" {
+		t.Errorf("unexpected runs output: %+v", meta.messages["runs"].value)
+	}
+
+	for _, expected := range []string{"RUN ID", "run-2   planned_and_finished  plan_only", "abc1234  "} {
+		if !strings.Contains(ui.OutputWriter.String(), expected) {
+			t.Errorf("expected run table to contain %q, but received:\n%s", expected, ui.OutputWriter.String())
+		}
+	}
+}
+
+func TestListRunsCommand_InvalidOptions(t *testing.T) {
+	testCases := [][]string{
+		{},
+		{"-workspace", "ws", "-operation", "apply"},
+		{"-workspace", "ws", "-created-after", "yesterday"},
+	}
+
+	for _, args := range testCases {
+		ui := cli.NewMockUi()
+		w := writer.NewWriter(ui)
+		cloudService := cloud.NewCloud(&tfe.Client{}, w)
+		cloudService.RunService = &listRunsStub{}
+		meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+		c := &ListRunsCommand{Meta: meta}
+		if code := c.Run(args); code != 1 {
+			t.Errorf("expected exit code 1 for %v, but received: %d", args, code)
+		}
+	}
+}
+
+func TestRunTable_MessageTruncation(t *testing.T) {
+	items := []*RunListItem{
+		{ID: "run-1", Message: "Größenänderung der Datenbank " + strings.Repeat("ü", 40)},
+		{ID: "run-2", Message: "short ✓\nsecond line"},
+	}
+
+	table := runTable(items)
+	if !utf8.ValidString(table) {
+		t.Errorf("expected valid UTF-8 table, but received: %q", table)
+	}
+	expected := "Größenänderung der Datenbank " + strings.Repeat("ü", 28) + "..."
+	if !strings.Contains(table, expected) {
+		t.Errorf("expected run table to contain %q, but received:\n%s", expected, table)
+	}
+	if !strings.Contains(table, "short ✓ second line") {
+		t.Errorf("expected short message on one line, but received:\n%s", table)
+	}
+}
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if v, err := parseTimeFlag("24h", now); err != nil || !v.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("expected duration before now, but received: %s, %v", v, err)
+	}
+	if v, err := parseTimeFlag("", now); err != nil || !v.IsZero() {
+		t.Errorf("expected zero time, but received: %s, %v", v, err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// configures how plan and apply logs are printed for the command
+func (c *Meta) useLogOptions(view string, hideRefresh bool) error {
+	logView, err := cloud.ParseLogView(view)
+	if err != nil {
+		return err
+	}
+	c.cloud.UseLogOptions(cloud.LogOptions{View: logView, HideRefresh: hideRefresh})
+	return nil
+}
+
+// adds the change summary and errors parsed from a plan or apply log as outputs,
+// so failed runs can be explained from the command result
+func (c *Meta) addLogOutputs(runLog *cloud.RunLog) {
+	if runLog == nil {
+		return
+	}
+	if runLog.ChangeSummary != nil {
+		c.addOutput("change_summary", runLog.ChangeSummary.Message)
+	}
+	if summary := runLog.ErrorSummary(); summary != "" {
+		c.addOutputWithOpts("error_summary", summary, &outputOpts{
+			stdOut:      true,
+			platformOut: true,
+			multiLine:   strings.Contains(summary, "\n"),
+		})
+	}
+}
+
+// prints task stages, plan log, cost estimation and policy checks of the plan phase, returns the parsed plan log
+func (c *Meta) readPlanLogs(run *tfe.Run, planLogTail *runLogTail, runError error, directory string) *cloud.RunLog {
+	// Pre Plan task stages
+	c.cloud.LogTaskStage(c.appCtx, run, tfe.PrePlan)
+	// Plan, already printed when streamed while the run was in progress
+	planLog, streamed, pLogErr := planLogTail.wait(runError)
+	if !streamed {
+		planLog, pLogErr = c.cloud.GetPlanLogs(c.appCtx, run.Plan.ID)
+	}
+	if pLogErr != nil {
+		c.writer.ErrorResult(fmt.Sprintf("failed to read plan logs: %s", pLogErr.Error()))
+	}
+	c.addLogOutputs(planLog)
+	c.reportDiagnostics(planLog, directory)
+	// Post Plan task stages
+	c.cloud.LogTaskStage(c.appCtx, run, tfe.PostPlan)
+	// cost estimation
+	c.cloud.LogCostEstimation(c.appCtx, run)
+	// sentinel policies
+	if policyLogErr := c.cloud.GetPolicyCheckLogs(c.appCtx, run); policyLogErr != nil {
+		c.writer.ErrorResult(fmt.Sprintf("failed to read policy check logs: %s", policyLogErr.Error()))
+	}
+	return planLog
+}
+
+// prints the pre-apply task stage and apply log
+func (c *Meta) readApplyLogs(run *tfe.Run, applyLogTail *runLogTail, applyError error, directory string) {
+	// pre-apply task stage
+	c.cloud.LogTaskStage(c.appCtx, run, tfe.PreApply)
+	// apply logs, already printed when streamed while the apply was in progress
+	applyLog, streamed, logErr := applyLogTail.wait(applyError)
+	if !streamed {
+		applyLog, logErr = c.cloud.GetApplyLogs(c.appCtx, run.Apply.ID)
+	}
+	if logErr != nil {
+		c.writer.ErrorResult(fmt.Sprintf("failed to read apply logs: %s", logErr.Error()))
+	}
+	c.addLogOutputs(applyLog)
+	c.reportDiagnostics(applyLog, directory)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+)
+
+func TestMeta_AddLogOutputs(t *testing.T) {
+	meta := NewMetaOpts(context.Background(), nil, &environment.CI{PlatformType: environment.Other})
+
+	meta.addLogOutputs(&cloud.RunLog{
+		ChangeSummary: &cloud.ChangeSummary{Add: 1, Message: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		Diagnostics: []*cloud.Diagnostic{
+			{Severity: cloud.DiagnosticWarning, Summary: "Deprecated attribute"},
+			{Severity: cloud.DiagnosticError, Summary: "Unsupported argument", Range: &cloud.DiagnosticRange{Filename: "main.tf", Start: cloud.DiagnosticPos{Line: 3}}},
+			{Severity: cloud.DiagnosticError, Summary: "Missing required argument"},
+		},
+	})
+
+	if v := meta.messages["change_summary"]; v == nil || v.value != "Plan: 1 to add, 0 to change, 0 to destroy." {
+		t.Errorf("unexpected change_summary output: %+v", v)
+	}
+	errorSummary := meta.messages["error_summary"]
+	if errorSummary == nil {
+		t.Fatal("expected error_summary output")
+	}
+	expected := "Error: Unsupported argument (main.tf line 3)\nError: Missing required argument"
+	if errorSummary.value != expected || !errorSummary.multiLine {
+		t.Errorf("expected multiline error_summary %q, but received: %+v", expected, errorSummary)
+	}
+
+	// logs without a summary or errors add no outputs
+	meta = NewMetaOpts(context.Background(), nil, &environment.CI{PlatformType: environment.Other})
+	meta.addLogOutputs(&cloud.RunLog{})
+	meta.addLogOutputs(nil)
+	if len(meta.messages) != 0 {
+		t.Errorf("expected no outputs, but received: %v", meta.messages)
+	}
+}
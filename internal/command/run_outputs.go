@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// options of reporting a created run, shared by run create and run wait
+type createdRunOpts struct {
+	workspace       string
+	planLog         *cloud.RunLog
+	resourceChanges bool
+	prComment       bool
+}
+
+// adds the run, plan, configuration version and cost estimation details shared by commands following a run
+func (c *Meta) addRunOutputs(run *tfe.Run, runLink string) {
+	if runLink != "" {
+		c.addOutput("run_link", runLink)
+	}
+	c.addOutput("run_id", run.ID)
+	c.addOutput("run_status", string(run.Status))
+	c.addOutput("run_message", run.Message)
+	if run.Plan != nil {
+		c.addOutput("plan_id", run.Plan.ID)
+		c.addOutput("plan_status", string(run.Plan.Status))
+	}
+	if run.ConfigurationVersion != nil {
+		c.addOutput("configuration_version_id", run.ConfigurationVersion.ID)
+	}
+
+	// add cost estimation info if enabled on run
+	if run.CostEstimate != nil {
+		c.addOutput("cost_estimation_id", run.CostEstimate.ID)
+		c.addOutput("cost_estimation_status", string(run.CostEstimate.Status))
+		if run.CostEstimate.ErrorMessage != "" {
+			c.writer.ErrorResult(fmt.Sprintf("Cost Estimation errored: %s", run.CostEstimate.ErrorMessage))
+		}
+	}
+
+	c.addOutputWithOpts("payload", run, &outputOpts{
+		stdOut:      false,
+		multiLine:   true,
+		platformOut: true,
+	})
+}
+
+// adds outputs, platform reports, summaries and the pull request comment of a created run once it completed
+func (c *Meta) addCreatedRunDetails(run *tfe.Run, opts *createdRunOpts) {
+	runLink := c.runLink(run)
+	c.addRunOutputs(run, runLink)
+
+	c.reportPlan(run.Plan)
+
+	var changes []*cloud.ResourceChange
+	if opts.resourceChanges && run.Plan.Status == tfe.PlanFinished {
+		changes = c.readResourceChanges(run.Plan.ID)
+	}
+
+	// additional run details are only fetched when a report is rendered
+	var summary *runSummary
+	if c.summaryWriter() != nil || opts.prComment {
+		summary = c.newRunSummary("HCP Terraform Run", run, runLink)
+		summary.changes = changes
+	}
+	c.writeSummary(summary)
+	if opts.prComment {
+		c.publishRunComment(opts.workspace, summary, opts.planLog)
+	}
+}
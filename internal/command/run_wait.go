@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+type WaitRunCommand struct {
+	*Meta
+
+	RunID string
+	// phase the run is waited for, defaults to where run create stops
+	Until     string
+	Directory string
+	PRComment bool
+	// report run as a GitHub check on the commit
+	GitHubCheck bool
+	// print the plan log while the run is in progress
+	StreamLogs bool
+	// how structured plan log lines are printed
+	LogView     string
+	HideRefresh bool
+	// render a table of resource changes from the JSON execution plan
+	ResourceChanges bool
+
+	// details parsed from the plan log
+	planLog *cloud.RunLog
+}
+
+func (c *WaitRunCommand) flags() *flag.FlagSet {
+	f := c.flagSet("run wait")
+	f.StringVar(&c.RunID, "run", "", "Existing HCP Terraform Run ID to wait for, usually created with `run create -wait=false`.")
+	f.StringVar(&c.Until, "until", "", "Phase the run is waited for: planned, post_plan_completed, cost_estimated, policy_checked, confirmable, applied or completed. Defaults to the phase a blocking `run create` waits for.")
+	f.StringVar(&c.Directory, "directory", "", "Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the plan.")
+	f.BoolVar(&c.PRComment, "pr-comment", false, "Creates or updates a pull request comment with the run summary. Requires `GITHUB_TOKEN` on GitHub Actions, `GITLAB_TOKEN` set to a project or personal access token on GitLab merge request pipelines.")
+	f.BoolVar(&c.GitHubCheck, "github-check", false, "Reports the run as a check on the triggering commit when running in GitHub Actions. Requires `GITHUB_TOKEN` with the `checks: write` permission.")
+	f.StringVar(&c.LogView, "log-view", string(cloud.LogViewCompact), "How structured plan log lines are printed: compact, changes, errors or raw.")
+	f.BoolVar(&c.HideRefresh, "hide-refresh", false, "Omits refresh progress of resources and data sources from the plan log.")
+	f.BoolVar(&c.ResourceChanges, "resource-changes", false, "Prints a table of resource changes from the JSON execution plan once the plan finished and adds it to summaries, pull request comments and the `resource_changes` output. Sensitive values are masked.")
+	f.BoolVar(&c.StreamLogs, "stream-logs", true, "Prints the plan log as it is written while the run is in progress, otherwise the log is read once the run completed.")
+	return f
+}
+
+func (c *WaitRunCommand) Run(args []string) int {
+	if err := c.setupCmd(args, c.flags()); err != nil {
+		return 1
+	}
+
+	if c.RunID == "" {
+		return c.waitError("waiting for a run requires a valid run id")
+	}
+
+	var phase cloud.RunPhase
+	var err error
+	if c.Until != "" {
+		phase, err = cloud.ParseRunPhase(c.Until)
+	}
+	if err == nil {
+		err = c.useLogOptions(c.LogView, c.HideRefresh)
+	}
+	if err != nil {
+		return c.waitError(err.Error())
+	}
+
+	// the workspace names the check and pull request comment like for run create
+	run, err := c.cloud.GetRun(c.appCtx, cloud.GetRunOptions{RunID: c.RunID})
+	if err != nil {
+		return c.waitError(fmt.Sprintf("error reading run %s in HCP Terraform: %s", c.RunID, err.Error()))
+	}
+	workspace := ""
+	if run.Workspace != nil {
+		workspace = run.Workspace.Name
+	}
+
+	check := c.newRunCheck(c.GitHubCheck, fmt.Sprintf("HCP Terraform / %s", workspace))
+	planLogTail := c.newPlanLogTail(c.StreamLogs)
+
+	run, waitError := c.cloud.WaitRun(c.appCtx, cloud.WaitRunOptions{
+		RunID: c.RunID,
+		Until: phase,
+		OnStatusChange: func(r *tfe.Run) {
+			check.update(r)
+			planLogTail.update(r)
+		},
+	})
+	check.complete(run, waitError)
+	if cloud.PlanStarted(run) {
+		c.planLog = c.readPlanLogs(run, planLogTail, waitError, c.Directory)
+	}
+
+	if waitError != nil {
+		c.addOutput("status", string(c.resolveStatus(waitError)))
+		c.addRunDetails(run, workspace)
+		c.writer.ErrorResult(fmt.Sprintf("error while waiting for run %s in HCP Terraform: %s", c.RunID, waitError.Error()))
+		c.writer.OutputResult(c.closeOutput())
+		return 1
+	}
+
+	c.addOutput("status", string(Success))
+	c.addRunDetails(run, workspace)
+	c.writer.OutputResult(c.closeOutput())
+	return 0
+}
+
+func (c *WaitRunCommand) waitError(msg string) int {
+	c.addOutput("status", string(Error))
+	c.closeOutput()
+	c.writer.ErrorResult(msg)
+	return 1
+}
+
+// adds the same outputs and reports as a blocking run create
+func (c *WaitRunCommand) addRunDetails(run *tfe.Run, workspace string) {
+	if run == nil {
+		return
+	}
+	c.addCreatedRunDetails(run, &createdRunOpts{
+		workspace:       workspace,
+		planLog:         c.planLog,
+		resourceChanges: c.ResourceChanges,
+		prComment:       c.PRComment,
+	})
+}
+
+func (c *WaitRunCommand) Help() string {
+	helpText := `
+Usage: tfci [global options] run wait [options]
+
+	Waits for a run created with "run create -wait=false", with the same logs, outputs and reports as a blocking "run create".
+	The run may be waited for in a later job than the one that created it.
+
+Global Options:
+
+	-hostname       The hostname of a Terraform Enterprise installation, if using Terraform Enterprise. Defaults to "app.terraform.io".
+
+	-token          The token used to authenticate with HCP Terraform. Defaults to reading "TF_API_TOKEN" environment variable.
+
+	-organization   HCP Terraform Organization Name.
+
+Options:
+
+	-run                    Existing HCP Terraform Run ID to wait for, usually created with "run create -wait=false".
+
+	-until                  Phase of the run lifecycle the run is waited for: "planned", "post_plan_completed", "cost_estimated", "policy_checked", "confirmable", "applied" or "completed". Defaults to the phase a blocking "run create" waits for: "completed" for plan-only runs, "applied" for auto-apply runs, otherwise "policy_checked" or "cost_estimated" when enabled, or "confirmable".
+
+	-directory              Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the plan.
+	-pr-comment             Creates or updates a pull request comment with the run summary. Requires "GITHUB_TOKEN" on GitHub Actions, "GITLAB_TOKEN" set to a project or personal access token on GitLab merge request pipelines.
+	-github-check           Reports the run as a check on the triggering commit when running in GitHub Actions. Requires "GITHUB_TOKEN" with the "checks: write" permission.
+	-log-view               How structured plan log lines are printed: "compact" (default), "changes", "errors" or "raw".
+	-hide-refresh           Omits refresh progress of resources and data sources from the plan log.
+	-resource-changes       Prints a table of resource changes from the JSON execution plan and adds it to summaries, pull request comments and the "resource_changes" output. Sensitive values are masked.
+	-stream-logs=false      Read the plan log once the run completed, instead of printing it while the run is in progress.
+	`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WaitRunCommand) Synopsis() string {
+	return "Waits for a run created without waiting to complete"
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// creates a pending run and waits for it to finish planning
+type waitRunStub struct {
+	watchRunStub
+	createOptions cloud.CreateRunOptions
+	waitOptions   cloud.WaitRunOptions
+}
+
+func (s *waitRunStub) CreateRun(_ context.Context, options cloud.CreateRunOptions) (*tfe.Run, error) {
+	s.createOptions = options
+	return &tfe.Run{
+		ID:        "run-123",
+		Status:    tfe.RunPending,
+		Workspace: &tfe.Workspace{ID: "ws-1"},
+		Plan:      &tfe.Plan{ID: "plan-123", Status: tfe.PlanPending},
+	}, nil
+}
+
+func (s *waitRunStub) WaitRun(_ context.Context, options cloud.WaitRunOptions) (*tfe.Run, error) {
+	s.waitOptions = options
+	return &tfe.Run{
+		ID:        options.RunID,
+		Status:    tfe.RunPlannedAndFinished,
+		Workspace: &tfe.Workspace{ID: "ws-1", Name: "ws"},
+		Plan:      &tfe.Plan{ID: "plan-123", Status: tfe.PlanFinished},
+	}, nil
+}
+
+func TestCreateRunCommand_NoWait(t *testing.T) {
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	stub := &waitRunStub{}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = stub
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+	c := &CreateRunCommand{Meta: meta}
+	if code := c.Run([]string{"-workspace", "ws", "-message", "test", "-wait=false"}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	if !stub.createOptions.NoWait {
+		t.Error("expected the run to be created without waiting")
+	}
+	if stub.planLogs {
+		t.Error("expected plan logs not to be read for a pending run")
+	}
+	expected := map[string]string{
+		"status":     string(Success),
+		"run_id":     "run-123",
+		"run_status": string(tfe.RunPending),
+		"run_link":   "https://app.terraform.io/app/org/workspaces/ws/runs/run-123",
+	}
+	for name, value := range expected {
+		if v := meta.messages[name]; v == nil || v.value != value {
+			t.Errorf("expected output %s %q, but received: %+v", name, value, v)
+		}
+	}
+}
+
+func TestWaitRunCommand(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected cloud.RunPhase
+	}{
+		{name: "desired-status", args: []string{"-run", "run-123", "-stream-logs=false"}},
+		{name: "until-planned", args: []string{"-run", "run-123", "-stream-logs=false", "-until", "planned"}, expected: cloud.RunPhasePlanned},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			w := writer.NewWriter(ui)
+			stub := &waitRunStub{}
+			cloudService := cloud.NewCloud(&tfe.Client{}, w)
+			cloudService.RunService = stub
+			meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+			c := &WaitRunCommand{Meta: meta}
+			if code := c.Run(tc.args); code != 0 {
+				t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+			}
+
+			if stub.waitOptions.RunID != "run-123" || stub.waitOptions.Until != tc.expected {
+				t.Errorf("unexpected wait options: %+v", stub.waitOptions)
+			}
+			if !stub.planLogs {
+				t.Error("expected plan logs to be read")
+			}
+			// same outputs as a blocking run create
+			expected := map[string]string{
+				"status":      string(Success),
+				"run_id":      "run-123",
+				"run_status":  string(tfe.RunPlannedAndFinished),
+				"plan_id":     "plan-123",
+				"plan_status": string(tfe.PlanFinished),
+			}
+			for name, value := range expected {
+				if v := meta.messages[name]; v == nil || v.value != value {
+					t.Errorf("expected output %s %q, but received: %+v", name, value, v)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitRunCommand_InvalidOptions(t *testing.T) {
+	testCases := [][]string{
+		{},
+		{"-run", "run-123", "-until", "finished"},
+	}
+
+	for _, args := range testCases {
+		ui := cli.NewMockUi()
+		w := writer.NewWriter(ui)
+		cloudService := cloud.NewCloud(&tfe.Client{}, w)
+		cloudService.RunService = &waitRunStub{}
+		meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+		c := &WaitRunCommand{Meta: meta}
+		if code := c.Run(args); code != 1 {
+			t.Errorf("expected exit code 1 for %v, but received: %d", args, code)
+		}
+	}
+}
+
+func TestCreateRunCommand_WaitUntil(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		code     int
+		expected cloud.RunPhase
+	}{
+		{name: "default", args: []string{"-workspace", "ws", "-message", "test"}},
+		{name: "cost-estimated", args: []string{"-workspace", "ws", "-message", "test", "-wait-until", "cost_estimated"}, expected: cloud.RunPhaseCostEstimated},
+		{name: "unsupported", args: []string{"-workspace", "ws", "-message", "test", "-wait-until", "estimated"}, code: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			w := writer.NewWriter(ui)
+			stub := &waitRunStub{}
+			cloudService := cloud.NewCloud(&tfe.Client{}, w)
+			cloudService.RunService = stub
+			meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+			c := &CreateRunCommand{Meta: meta}
+			if code := c.Run(append(tc.args, "-stream-logs=false")); code != tc.code {
+				t.Fatalf("expected exit code %d, but received: %d, %s", tc.code, code, ui.ErrorWriter.String())
+			}
+			if stub.createOptions.WaitUntil != tc.expected {
+				t.Errorf("expected wait until %q, but received: %q", tc.expected, stub.createOptions.WaitUntil)
+			}
+		})
+	}
+}
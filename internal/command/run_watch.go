@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+type WatchRunCommand struct {
+	*Meta
+
+	RunID string
+	// phase the run is followed until
+	Until     string
+	Directory string
+	// print plan and apply logs while the run is in progress
+	StreamLogs bool
+	// how structured plan and apply log lines are printed
+	LogView     string
+	HideRefresh bool
+}
+
+func (c *WatchRunCommand) flags() *flag.FlagSet {
+	f := c.flagSet("run watch")
+	f.StringVar(&c.RunID, "run", "", "Existing HCP Terraform Run ID to watch.")
+	f.StringVar(&c.Until, "until", string(cloud.RunPhaseCompleted), "Phase the run is watched until: planned, post_plan_completed, cost_estimated, policy_checked, confirmable, applied or completed.")
+	f.StringVar(&c.Directory, "directory", "", "Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the plan and apply.")
+	f.StringVar(&c.LogView, "log-view", string(cloud.LogViewCompact), "How structured plan and apply log lines are printed: compact, changes, errors or raw.")
+	f.BoolVar(&c.HideRefresh, "hide-refresh", false, "Omits refresh progress of resources and data sources from the plan and apply logs.")
+	f.BoolVar(&c.StreamLogs, "stream-logs", true, "Prints plan and apply logs as they are written while the run is in progress, otherwise logs are read once the run reached the phase.")
+	return f
+}
+
+func (c *WatchRunCommand) Run(args []string) int {
+	if err := c.setupCmd(args, c.flags()); err != nil {
+		return 1
+	}
+
+	if c.RunID == "" {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult("watching a run requires a valid run id")
+		return 1
+	}
+
+	phase, err := cloud.ParseRunPhase(c.Until)
+	if err == nil {
+		err = c.useLogOptions(c.LogView, c.HideRefresh)
+	}
+	if err != nil {
+		c.addOutput("status", string(Error))
+		c.closeOutput()
+		c.writer.ErrorResult(err.Error())
+		return 1
+	}
+
+	planLogTail := c.newPlanLogTail(c.StreamLogs)
+	applyLogTail := c.newApplyLogTail(c.StreamLogs)
+
+	run, watchError := c.cloud.WatchRun(c.appCtx, cloud.WatchRunOptions{
+		RunID: c.RunID,
+		Until: phase,
+		OnStatusChange: func(r *tfe.Run) {
+			planLogTail.update(r)
+			applyLogTail.update(r)
+		},
+	})
+	if cloud.PlanStarted(run) {
+		c.readPlanLogs(run, planLogTail, watchError, c.Directory)
+	}
+	if cloud.ApplyStarted(run) {
+		c.readApplyLogs(run, applyLogTail, watchError, c.Directory)
+	}
+
+	if watchError != nil {
+		c.addOutput("status", string(c.resolveStatus(watchError)))
+		c.addRunDetails(run)
+		c.writer.ErrorResult(fmt.Sprintf("error while watching run %s in HCP Terraform: %s", c.RunID, watchError.Error()))
+		c.writer.OutputResult(c.closeOutput())
+		return 1
+	}
+
+	c.addOutput("status", string(Success))
+	c.addRunDetails(run)
+	c.writer.OutputResult(c.closeOutput())
+	return 0
+}
+
+// adds the same outputs as run create
+func (c *WatchRunCommand) addRunDetails(run *tfe.Run) {
+	if run == nil {
+		return
+	}
+	runLink := c.runLink(run)
+	c.addRunOutputs(run, runLink)
+	c.reportPlan(run.Plan)
+	c.writeRunSummary("HCP Terraform Run", run, runLink)
+}
+
+func (c *WatchRunCommand) Help() string {
+	helpText := `
+Usage: tfci [global options] run watch [options]
+
+	Follows an existing run until it reaches a phase, printing status changes, task stages, plan and apply logs, cost estimation and policy checks.
+	Runs started by any source can be watched, such as VCS-triggered runs, runs queued by other pipelines and auto-applied runs.
+
+Global Options:
+
+	-hostname       The hostname of a Terraform Enterprise installation, if using Terraform Enterprise. Defaults to "app.terraform.io".
+
+	-token          The token used to authenticate with HCP Terraform. Defaults to reading "TF_API_TOKEN" environment variable.
+
+	-organization   HCP Terraform Organization Name.
+
+Options:
+
+	-run          Existing HCP Terraform Run ID to watch.
+
+	-until        Phase of the run lifecycle the run is watched until: "planned", "post_plan_completed", "cost_estimated", "policy_checked", "confirmable", "applied" or "completed" (default).
+
+	-directory    Path of the uploaded configuration, relative to the repository root. Used to annotate files with diagnostics from the plan and apply.
+
+	-log-view     How structured plan and apply log lines are printed: "compact" (default), "changes", "errors" or "raw".
+
+	-hide-refresh  Omits refresh progress of resources and data sources from the plan and apply logs.
+
+	-stream-logs=false  Read plan and apply logs once the run reached the phase, instead of printing them while the run is in progress.
+	`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WatchRunCommand) Synopsis() string {
+	return "Follows an existing run until it reaches a phase"
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// returns an applied run and records which logs were read
+type watchRunStub struct {
+	applyRunStub
+	options   cloud.WatchRunOptions
+	planLogs  bool
+	applyLogs bool
+}
+
+func (s *watchRunStub) WatchRun(_ context.Context, options cloud.WatchRunOptions) (*tfe.Run, error) {
+	s.options = options
+	return &tfe.Run{
+		ID:                   options.RunID,
+		Status:               tfe.RunApplied,
+		Message:              "Triggered via UI",
+		Workspace:            &tfe.Workspace{ID: "ws-1"},
+		ConfigurationVersion: &tfe.ConfigurationVersion{ID: "cv-1"},
+		Plan:                 &tfe.Plan{ID: "plan-123", Status: tfe.PlanFinished},
+		Apply:                &tfe.Apply{ID: "apply-123", Status: tfe.ApplyFinished},
+	}, nil
+}
+
+func (s *watchRunStub) GetPlanLogs(context.Context, string) (*cloud.RunLog, error) {
+	s.planLogs = true
+	return &cloud.RunLog{}, nil
+}
+
+func (s *watchRunStub) GetApplyLogs(context.Context, string) (*cloud.RunLog, error) {
+	s.applyLogs = true
+	return &cloud.RunLog{}, nil
+}
+
+func (s *watchRunStub) LogCostEstimation(context.Context, *tfe.Run) {}
+
+func (s *watchRunStub) GetPolicyCheckLogs(context.Context, *tfe.Run) error {
+	return nil
+}
+
+func TestWatchRunCommand(t *testing.T) {
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	stub := &watchRunStub{}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = stub
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithOrg("org"), WithWriter(w))
+
+	c := &WatchRunCommand{Meta: meta}
+	if code := c.Run([]string{"-run", "run-123", "-stream-logs=false"}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	if stub.options.RunID != "run-123" || stub.options.Until != cloud.RunPhaseCompleted {
+		t.Errorf("unexpected watch options: %+v", stub.options)
+	}
+	if !stub.planLogs || !stub.applyLogs {
+		t.Errorf("expected plan and apply logs to be read, plan: %t, apply: %t", stub.planLogs, stub.applyLogs)
+	}
+
+	// same outputs as run create
+	expected := map[string]string{
+		"status":                   string(Success),
+		"run_id":                   "run-123",
+		"run_status":               string(tfe.RunApplied),
+		"run_message":              "Triggered via UI",
+		"run_link":                 "https://app.terraform.io/app/org/workspaces/ws/runs/run-123",
+		"plan_id":                  "plan-123",
+		"plan_status":              string(tfe.PlanFinished),
+		"configuration_version_id": "cv-1",
+	}
+	for name, value := range expected {
+		if v := meta.messages[name]; v == nil || v.value != value {
+			t.Errorf("expected output %s %q, but received: %+v", name, value, v)
+		}
+	}
+	if meta.messages["payload"] == nil {
+		t.Error("expected payload output")
+	}
+}
+
+func TestWatchRunCommand_InvalidPhase(t *testing.T) {
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = &watchRunStub{}
+	meta := NewMetaOpts(context.Background(), cloudService, &environment.CI{PlatformType: environment.Other}, WithWriter(w))
+
+	c := &WatchRunCommand{Meta: meta}
+	if code := c.Run([]string{"-run", "run-123", "-until", "finished"}); code != 1 {
+		t.Fatalf("expected exit code 1, but received: %d", code)
+	}
+}
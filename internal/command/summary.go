@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+)
+
+// runSummary collects run, plan and policy details rendered as a markdown report
+type runSummary struct {
+	title        string
+	runID        string
+	runLink      string
+	runStatus    string
+	plan         *tfe.Plan
+	costEstimate *tfe.CostEstimate
+	policy       *cloud.PolicyEvaluation
+	taskStages   []*tfe.TaskStage
+	// resource changes of the JSON execution plan, when requested
+	changes []*cloud.ResourceChange
+}
+
+func (s *runSummary) markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "### %s\n\n", s.title)
+	b.WriteString("| | |\n| --- | --- |\n")
+	if s.runID != "" {
+		if s.runLink != "" {
+			fmt.Fprintf(&b, "| Run | [%s](%s) |\n", s.runID, s.runLink)
+		} else {
+			fmt.Fprintf(&b, "| Run | `%s` |\n", s.runID)
+		}
+	}
+	if s.runStatus != "" {
+		fmt.Fprintf(&b, "| Status | `%s` |\n", s.runStatus)
+	}
+	if s.plan != nil {
+		fmt.Fprintf(&b, "| Plan | `%s` |\n", s.plan.Status)
+		fmt.Fprintf(&b, "| Changes | **%d** to add, **%d** to change, **%d** to destroy |\n", s.plan.ResourceAdditions, s.plan.ResourceChanges, s.plan.ResourceDestructions)
+	}
+	if s.costEstimate != nil && s.costEstimate.Status == tfe.CostEstimateFinished {
+		fmt.Fprintf(&b, "| Cost Estimate | %s → %s (delta %s / month) |\n", formatCost(s.costEstimate.PriorMonthlyCost), formatCost(s.costEstimate.ProposedMonthlyCost), formatCost(s.costEstimate.DeltaMonthlyCost))
+	}
+
+	if len(s.changes) > 0 {
+		b.WriteString("\n#### Resource Changes\n")
+		b.WriteString(changeTableMarkdown(s.changes))
+	}
+
+	if s.policy != nil && s.policy.TotalCount > 0 {
+		b.WriteString("\n#### Policies\n\n")
+		b.WriteString("| Total | ✅ Passed | ⚠️ Advisory Failed | 🚫 Mandatory Failed | ❌ Errored |\n| --- | --- | --- | --- | --- |\n")
+		fmt.Fprintf(&b, "| %d | %d | %d | %d | %d |\n", s.policy.TotalCount, s.policy.PassedCount, s.policy.AdvisoryFailedCount, s.policy.MandatoryFailedCount, s.policy.ErroredCount)
+		if s.policy.MandatoryFailedCount > 0 {
+			b.WriteString("\n**Failed Mandatory Policies**\n\n")
+			for _, policy := range s.policy.FailedPolicies {
+				if policy.EnforcementLevel != cloud.EnforcementMandatory {
+					continue
+				}
+				if policy.Description != "" {
+					fmt.Fprintf(&b, "- `%s`: %s\n", policy.PolicyName, policy.Description)
+				} else {
+					fmt.Fprintf(&b, "- `%s`\n", policy.PolicyName)
+				}
+			}
+		}
+	}
+
+	if len(s.taskStages) > 0 {
+		b.WriteString("\n#### Run Tasks\n\n")
+		b.WriteString("| Stage | Status | Results |\n| --- | --- | --- |\n")
+		for _, stage := range s.taskStages {
+			results := []string{}
+			for _, result := range stage.TaskResults {
+				if result.TaskName == "" {
+					continue
+				}
+				results = append(results, fmt.Sprintf("%s: `%s`", result.TaskName, result.Status))
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", stage.Stage, stage.Status, strings.Join(results, "<br>"))
+		}
+	}
+
+	return b.String()
+}
+
+// cost estimates are returned as unformatted decimal strings, eg. "12.5"
+func formatCost(cost string) string {
+	if cost == "" {
+		return "n/a"
+	}
+	if strings.HasPrefix(cost, "-") {
+		return "-$" + strings.TrimPrefix(cost, "-")
+	}
+	return "$" + cost
+}
+
+// returns platform summary writer when running in CI that supports job summaries
+func (c *Meta) summaryWriter() environment.SummaryWriter {
+	if c.env == nil || c.env.Context == nil {
+		return nil
+	}
+	if sw, ok := c.env.Context.(environment.SummaryWriter); ok {
+		return sw
+	}
+	return nil
+}
+
+// writes markdown report to platform, if supported
+func (c *Meta) writeSummary(summary *runSummary) {
+	sw := c.summaryWriter()
+	if sw == nil || summary == nil {
+		return
+	}
+	if err := sw.WriteSummary(summary.markdown()); err != nil {
+		log.Printf("[ERROR] problem writing platform summary: %s", err.Error())
+	}
+}
+
+// writes run report to platform, additional run details are only fetched when summaries are supported
+func (c *Meta) writeRunSummary(title string, run *tfe.Run, runLink string) {
+	if c.summaryWriter() == nil || run == nil {
+		return
+	}
+	c.writeSummary(c.newRunSummary(title, run, runLink))
+}
+
+// builds summary from run details, including task stage results and policy evaluation when available
+func (c *Meta) newRunSummary(title string, run *tfe.Run, runLink string) *runSummary {
+	summary := &runSummary{
+		title:        title,
+		runID:        run.ID,
+		runLink:      runLink,
+		runStatus:    string(run.Status),
+		plan:         run.Plan,
+		costEstimate: run.CostEstimate,
+	}
+
+	taskStages, err := c.cloud.ReadTaskStages(c.appCtx, run)
+	if err != nil {
+		log.Printf("[ERROR] problem reading task stages for summary: %s", err.Error())
+	}
+	summary.taskStages = taskStages
+
+	eval, err := c.cloud.GetPolicyEvaluation(c.appCtx, cloud.GetPolicyEvaluationOptions{
+		RunID:  run.ID,
+		NoWait: true,
+	})
+	if err != nil && !errors.Is(err, cloud.ErrNoPolicyCheck) {
+		log.Printf("[ERROR] problem reading policy evaluation for summary: %s", err.Error())
+	}
+	summary.policy = eval
+
+	return summary
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+func TestRunSummary_Markdown(t *testing.T) {
+	testCases := []struct {
+		name     string
+		summary  *runSummary
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "run-details",
+			summary: &runSummary{
+				title:     "HCP Terraform Run",
+				runID:     "run-abc123",
+				runLink:   "https://app.terraform.io/app/org/workspaces/ws/runs/run-abc123",
+				runStatus: string(tfe.RunPlannedAndFinished),
+				plan: &tfe.Plan{
+					Status:               tfe.PlanFinished,
+					ResourceAdditions:    1,
+					ResourceChanges:      2,
+					ResourceDestructions: 3,
+				},
+				costEstimate: &tfe.CostEstimate{
+					Status:              tfe.CostEstimateFinished,
+					PriorMonthlyCost:    "10.00",
+					ProposedMonthlyCost: "5.00",
+					DeltaMonthlyCost:    "-5.00",
+				},
+			},
+			contains: []string{
+				"### HCP Terraform Run",
+				"| Run | [run-abc123](https://app.terraform.io/app/org/workspaces/ws/runs/run-abc123) |",
+				"| Status | `planned_and_finished` |",
+				"**1** to add, **2** to change, **3** to destroy",
+				"$10.00 → $5.00 (delta -$5.00 / month)",
+			},
+			excludes: []string{"#### Policies", "#### Run Tasks"},
+		},
+		{
+			name: "policy-and-task-stages",
+			summary: &runSummary{
+				title: "HCP Terraform Run",
+				runID: "run-abc123",
+				policy: &cloud.PolicyEvaluation{
+					RunID:                "run-abc123",
+					TotalCount:           3,
+					PassedCount:          2,
+					MandatoryFailedCount: 1,
+					FailedPolicies: []cloud.PolicyDetail{
+						{
+							PolicyName:       "aws-cost-limit",
+							EnforcementLevel: cloud.EnforcementMandatory,
+							Status:           cloud.PolicyStatusFailed,
+							Description:      "exceeds threshold",
+						},
+					},
+				},
+				taskStages: []*tfe.TaskStage{
+					{
+						Stage:  tfe.PostPlan,
+						Status: tfe.TaskStageFailed,
+						TaskResults: []*tfe.TaskResult{
+							{TaskName: "scanner", Status: tfe.TaskFailed},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"#### Policies",
+				"| 3 | 2 | 0 | 1 | 0 |",
+				"- `aws-cost-limit`: exceeds threshold",
+				"#### Run Tasks",
+				"| post_plan | `failed` | scanner: `failed` |",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.summary.markdown()
+			for _, expected := range tc.contains {
+				if !strings.Contains(actual, expected) {
+					t.Errorf("expected %q in summary, but received: %q", expected, actual)
+				}
+			}
+			for _, unexpected := range tc.excludes {
+				if strings.Contains(actual, unexpected) {
+					t.Errorf("expected %q to not be in summary, but received: %q", unexpected, actual)
+				}
+			}
+		})
+	}
+}
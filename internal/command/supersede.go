@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// identifies runs created for the same pull request, or branch outside of pull requests, in run messages
+func (c *Meta) supersedeTag() (tag string, target string, err error) {
+	if c.env == nil || c.env.Context == nil {
+		return "", "", errors.New("-supersede requires a pull request or branch from the CI context")
+	}
+	ctx := c.env.Context
+	repository := ctx.RepositoryURL()
+	for _, scheme := range []string{"https://", "http://"} {
+		repository = strings.TrimPrefix(repository, scheme)
+	}
+
+	if pr := ctx.PullRequest(); pr != nil && pr.Number > 0 {
+		target = fmt.Sprintf("%s#%d", repository, pr.Number)
+	} else if ref := ctx.Ref(); ref != "" {
+		target = fmt.Sprintf("%s@%s", repository, ref)
+	} else {
+		return "", "", errors.New("-supersede requires a pull request or branch from the CI context")
+	}
+	return fmt.Sprintf("[tfci:%s]", target), target, nil
+}
+
+// appends the tag to the run message, so later runs of the same branch or pull request find the run
+func tagRunMessage(message string, tag string) string {
+	if strings.Contains(message, tag) {
+		return message
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s", message, tag))
+}
+
+// discards or cancels older runs of the same branch or pull request, failures do not fail the new run
+func (c *CreateRunCommand) supersedeRuns(run *tfe.Run, tag string, target string) {
+	superseded, err := c.cloud.SupersedeRuns(c.appCtx, cloud.SupersedeRunsOptions{
+		Organization: c.organization,
+		Workspace:    c.Workspace,
+		Tag:          tag,
+		RunID:        run.ID,
+		CreatedAt:    run.CreatedAt,
+		Comment:      fmt.Sprintf("Superseded by run %s for %s, created by tfci.", run.ID, target),
+	})
+	if err != nil {
+		c.writer.Error(fmt.Sprintf("unable to supersede runs of %s: %s", target, err.Error()))
+	}
+
+	ids := make([]string, 0, len(superseded))
+	for _, r := range superseded {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) > 0 {
+		c.writer.Output(fmt.Sprintf("Superseded %d run(s) of %s: %s", len(ids), target, strings.Join(ids, ", ")))
+	}
+	c.addOutput("superseded_run_ids", strings.Join(ids, ","))
+}
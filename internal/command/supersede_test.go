@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-tfe"
+	"github.com/hashicorp/tfci/internal/cloud"
+	"github.com/hashicorp/tfci/internal/environment"
+	"github.com/hashicorp/tfci/internal/writer"
+	"github.com/mitchellh/cli"
+)
+
+// records superseded runs of the created run
+type supersedeRunStub struct {
+	waitRunStub
+	supersedeOptions cloud.SupersedeRunsOptions
+}
+
+func (s *supersedeRunStub) SupersedeRuns(_ context.Context, options cloud.SupersedeRunsOptions) ([]*tfe.Run, error) {
+	s.supersedeOptions = options
+	return []*tfe.Run{{ID: "run-1"}, {ID: "run-2"}}, nil
+}
+
+func TestSupersedeTag(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      *environment.CI
+		expected string
+	}{
+		{
+			name:     "pull-request",
+			env:      &environment.CI{PlatformType: environment.GitHub, Context: &testContext{pr: &environment.PullRequest{Number: 7}}},
+			expected: "[tfci:github.com/octocat/infra#7]",
+		},
+		{
+			name:     "branch",
+			env:      &environment.CI{PlatformType: environment.GitHub, Context: &testContext{}},
+			expected: "[tfci:github.com/octocat/infra@feature/network]",
+		},
+		{
+			name: "outside-ci",
+			env:  &environment.CI{PlatformType: environment.Other},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta := &Meta{env: tc.env}
+			tag, _, err := meta.supersedeTag()
+			if tc.expected == "" {
+				if err == nil {
+					t.Errorf("expected error, but received tag: %q", tag)
+				}
+				return
+			}
+			if err != nil || tag != tc.expected {
+				t.Errorf("expected tag %q, but received: %q, %v", tc.expected, tag, err)
+			}
+		})
+	}
+}
+
+func TestTagRunMessage(t *testing.T) {
+	tag := "[tfci:github.com/octocat/infra#7]"
+	if actual := tagRunMessage("Deploy", tag); actual != "Deploy "+tag {
+		t.Errorf("expected tagged message, but received: %q", actual)
+	}
+	if actual := tagRunMessage("Deploy "+tag, tag); actual != "Deploy "+tag {
+		t.Errorf("expected message to be tagged once, but received: %q", actual)
+	}
+}
+
+func TestCreateRunCommand_Supersede(t *testing.T) {
+	ui := cli.NewMockUi()
+	w := writer.NewWriter(ui)
+	stub := &supersedeRunStub{}
+	cloudService := cloud.NewCloud(&tfe.Client{}, w)
+	cloudService.RunService = stub
+	env := &environment.CI{PlatformType: environment.GitHub, Context: &testContext{pr: &environment.PullRequest{Number: 7}}}
+	meta := NewMetaOpts(context.Background(), cloudService, env, WithOrg("org"), WithWriter(w))
+
+	c := &CreateRunCommand{Meta: meta}
+	if code := c.Run([]string{"-workspace", "ws", "-message", "Deploy", "-supersede", "-stream-logs=false", "-github-check=false"}); code != 0 {
+		t.Fatalf("expected exit code 0, but received: %d, %s", code, ui.ErrorWriter.String())
+	}
+
+	tag := "[tfci:github.com/octocat/infra#7]"
+	if !stub.createOptions.NoWait || stub.createOptions.Message != "Deploy "+tag {
+		t.Errorf("expected tagged run created without waiting, but received: %+v", stub.createOptions)
+	}
+	if stub.supersedeOptions.RunID != "run-123" || stub.supersedeOptions.Tag != tag || stub.supersedeOptions.Workspace != "ws" {
+		t.Errorf("unexpected supersede options: %+v", stub.supersedeOptions)
+	}
+	if stub.waitOptions.RunID != "run-123" {
+		t.Errorf("expected the new run to be waited for after superseding, but received: %+v", stub.waitOptions)
+	}
+
+	expected := map[string]string{
+		"status":             string(Success),
+		"run_status":         string(tfe.RunPlannedAndFinished),
+		"superseded_run_ids": "run-1,run-2",
+	}
+	for name, value := range expected {
+		if v := meta.messages[name]; v == nil || v.value != value {
+			t.Errorf("expected output %s %q, but received: %+v", name, value, v)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Sourced from: https://learn.microsoft.com/en-us/azure/devops/pipelines/build/variables
+type AzureDevOpsContext struct {
+	// The ID of the record for the completed build.
+	buildId string
+	// The name of the completed build, also known as the run number.
+	buildNumber string
+	// The latest version control change of the triggering repo that is included in this build.
+	sourceVersion string
+	// The person who pushed or checked in the changes.
+	requestedFor string
+	// The branch of the triggering repo the build was queued for.
+	sourceBranchName string
+	// A temporary folder that is cleaned after each pipeline job.
+	agentTempDirectory string
+	// The URI of the team foundation collection. For example: https://dev.azure.com/fabrikamfiber/
+	collectionURI string
+	// The name of the project that contains this build.
+	teamProject string
+	// The URL for the triggering repository.
+	repositoryURI string
+	// The ID of the pull request that caused this build, only set for pull request validation builds.
+	pullRequestID string
+	// The number of the pull request that caused this build, set for GitHub and Bitbucket pull requests instead of the ID.
+	pullRequestNumber string
+	// The branch that is being reviewed in a pull request, eg. refs/heads/feature
+	pullRequestSourceBranch string
+	// The branch that is the target of a pull request, eg. refs/heads/main
+	pullRequestTargetBranch string
+	// The commit that is being reviewed in a pull request.
+	pullRequestSourceCommitID string
+	// where logging commands are written, the agent reads them from stdout
+	stdout io.Writer
+	// The map containing output data
+	output OutputMap
+}
+
+func (az *AzureDevOpsContext) ID() string {
+	return fmt.Sprintf("azdo-%s", az.buildId)
+}
+
+func (az *AzureDevOpsContext) SHA() string {
+	return az.sourceVersion
+}
+
+func (az *AzureDevOpsContext) SHAShort() string {
+	if len(az.sourceVersion) > 7 {
+		return az.sourceVersion[:7]
+	}
+	return az.sourceVersion
+}
+
+func (az *AzureDevOpsContext) Author() string {
+	return az.requestedFor
+}
+
+func (az *AzureDevOpsContext) WriteDir() string {
+	return az.agentTempDirectory
+}
+
+func (az *AzureDevOpsContext) SetOutput(output OutputMap) {
+	az.output = output
+}
+
+func (az *AzureDevOpsContext) CloseOutput() error {
+	log.Printf("[DEBUG] Azure DevOps flushing output")
+
+	for k, v := range az.output {
+		value := v.String()
+		// logging commands are line based, multiline values are stored in a file
+		// and the output variable references the file path instead
+		if v.MultiLine() {
+			path, err := az.writeOutputFile(k, value)
+			if err != nil {
+				return err
+			}
+			value = path
+		}
+
+		if _, err := fmt.Fprintln(az.stdout, setVariableCommand(k, value)); err != nil {
+			return err
+		}
+	}
+
+	// reset output
+	az.output = make(map[string]OutputWriter)
+
+	return nil
+}
+
+// sets where logging commands are written, defaults to stdout
+func (az *AzureDevOpsContext) SetCommandWriter(w io.Writer) {
+	az.stdout = w
+}
+
+func (az *AzureDevOpsContext) writeOutputFile(name string, data string) (string, error) {
+	path := filepath.Join(az.WriteDir(), generateArtifactFileName("json", "tfci", az.buildId, name))
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// formats value as an output variable logging command
+// https://learn.microsoft.com/en-us/azure/devops/pipelines/scripts/logging-commands#setvariable-initialize-or-modify-the-value-of-a-variable
+func setVariableCommand(name string, value string) string {
+	return fmt.Sprintf("##vso[task.setvariable variable=%s;isOutput=true]%s", name, escapeLoggingCommand(value))
+}
+
+// logging command values cannot contain raw line breaks
+func escapeLoggingCommand(value string) string {
+	replacer := strings.NewReplacer(
+		"%", "%AZP25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+	return replacer.Replace(value)
+}
+
+func (az *AzureDevOpsContext) Ref() string {
+	if az.pullRequestSourceBranch != "" {
+		return strings.TrimPrefix(az.pullRequestSourceBranch, "refs/heads/")
+	}
+	return az.sourceBranchName
+}
+
+func (az *AzureDevOpsContext) PullRequest() *PullRequest {
+	id := az.pullRequestNumber
+	if id == "" {
+		id = az.pullRequestID
+	}
+	number, err := strconv.Atoi(id)
+	if err != nil {
+		return nil
+	}
+	pr := &PullRequest{
+		Number:  number,
+		HeadRef: strings.TrimPrefix(az.pullRequestSourceBranch, "refs/heads/"),
+		BaseRef: strings.TrimPrefix(az.pullRequestTargetBranch, "refs/heads/"),
+		HeadSHA: az.pullRequestSourceCommitID,
+		// pull request validation builds check out the merge commit
+		MergeCommitSHA: az.sourceVersion,
+	}
+	if az.pullRequestNumber == "" && az.repositoryURI != "" {
+		// Azure Repos
+		pr.URL = fmt.Sprintf("%s/pullrequest/%d", az.repositoryURI, number)
+	}
+	return pr
+}
+
+func (az *AzureDevOpsContext) ServerURL() string {
+	return strings.TrimSuffix(az.collectionURI, "/")
+}
+
+func (az *AzureDevOpsContext) RepositoryURL() string {
+	return az.repositoryURI
+}
+
+func (az *AzureDevOpsContext) JobURL() string {
+	if az.collectionURI == "" || az.teamProject == "" || az.buildId == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/_build/results?buildId=%s", strings.TrimSuffix(az.collectionURI, "/"), url.PathEscape(az.teamProject), az.buildId)
+}
+
+func newAzureDevOpsContext(getenv GetEnv) *AzureDevOpsContext {
+	return &AzureDevOpsContext{
+		buildId:                   getenv("BUILD_BUILDID"),
+		buildNumber:               getenv("BUILD_BUILDNUMBER"),
+		sourceVersion:             getenv("BUILD_SOURCEVERSION"),
+		requestedFor:              getenv("BUILD_REQUESTEDFOR"),
+		sourceBranchName:          getenv("BUILD_SOURCEBRANCHNAME"),
+		agentTempDirectory:        getenv("AGENT_TEMPDIRECTORY"),
+		collectionURI:             getenv("SYSTEM_COLLECTIONURI"),
+		teamProject:               getenv("SYSTEM_TEAMPROJECT"),
+		repositoryURI:             getenv("BUILD_REPOSITORY_URI"),
+		pullRequestID:             getenv("SYSTEM_PULLREQUEST_PULLREQUESTID"),
+		pullRequestNumber:         getenv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER"),
+		pullRequestSourceBranch:   getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"),
+		pullRequestTargetBranch:   getenv("SYSTEM_PULLREQUEST_TARGETBRANCH"),
+		pullRequestSourceCommitID: getenv("SYSTEM_PULLREQUEST_SOURCECOMMITID"),
+		stdout:                    os.Stdout,
+		output:                    make(map[string]OutputWriter),
+	}
+}
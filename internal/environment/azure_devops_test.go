@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func getAzureDevOpsEnvMock(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"TF_BUILD":            "True",
+		"BUILD_BUILDID":       "1024",
+		"BUILD_SOURCEVERSION": randomSha(t),
+		"BUILD_REQUESTEDFOR":  "Jane Doe",
+		"AGENT_TEMPDIRECTORY": t.TempDir(),
+	}
+}
+
+func Test_AzureDevOpsContext(t *testing.T) {
+	env := getAzureDevOpsEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	azdo := newAzureDevOpsContext(getenv)
+
+	expectedID := fmt.Sprintf("azdo-%s", env["BUILD_BUILDID"])
+	if actualID := azdo.ID(); actualID != expectedID {
+		t.Errorf("expected %s, but received: %s", expectedID, actualID)
+	}
+
+	if actualSHA := azdo.SHA(); actualSHA != env["BUILD_SOURCEVERSION"] {
+		t.Errorf("expected %s, but received: %s", env["BUILD_SOURCEVERSION"], actualSHA)
+	}
+
+	if actualShort := azdo.SHAShort(); actualShort != env["BUILD_SOURCEVERSION"][:7] {
+		t.Errorf("expected %s, but received: %s", env["BUILD_SOURCEVERSION"][:7], actualShort)
+	}
+
+	if actualAuthor := azdo.Author(); actualAuthor != env["BUILD_REQUESTEDFOR"] {
+		t.Errorf("expected %s, but received: %s", env["BUILD_REQUESTEDFOR"], actualAuthor)
+	}
+
+	if actualDir := azdo.WriteDir(); actualDir != env["AGENT_TEMPDIRECTORY"] {
+		t.Errorf("expected %s, but received: %s", env["AGENT_TEMPDIRECTORY"], actualDir)
+	}
+}
+
+func Test_AzureDevOpsOutput(t *testing.T) {
+	env := getAzureDevOpsEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	azdo := newAzureDevOpsContext(getenv)
+	stdout := new(bytes.Buffer)
+	azdo.stdout = stdout
+
+	azdo.SetOutput(OutputMap{
+		"run_id":  &testOutput{val: "run-abc123"},
+		"payload": &testOutput{val: "{\n  \"pk\": \"pv\"\n}", multiLine: true},
+	})
+
+	if err := azdo.CloseOutput(); err != nil {
+		t.Fatalf("error closing output: %s", err.Error())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 logging commands, but received: %d", len(lines))
+	}
+
+	expectedRunID := "##vso[task.setvariable variable=run_id;isOutput=true]run-abc123"
+	if !strings.Contains(stdout.String(), expectedRunID) {
+		t.Errorf("expected %q in output, but received: %q", expectedRunID, stdout.String())
+	}
+
+	// multiline values reference a file stored in the agent temp directory
+	prefix := "##vso[task.setvariable variable=payload;isOutput=true]"
+	var payloadPath string
+	for _, l := range lines {
+		if strings.HasPrefix(l, prefix) {
+			payloadPath = strings.TrimPrefix(l, prefix)
+		}
+	}
+	if !strings.HasPrefix(payloadPath, env["AGENT_TEMPDIRECTORY"]) {
+		t.Fatalf("expected payload path within %s, but received: %q", env["AGENT_TEMPDIRECTORY"], payloadPath)
+	}
+
+	contents, err := os.ReadFile(payloadPath)
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+	if string(contents) != "{\n  \"pk\": \"pv\"\n}" {
+		t.Errorf("unexpected payload file contents: %q", string(contents))
+	}
+}
+
+func Test_AzureDevOpsEscapeLoggingCommand(t *testing.T) {
+	actual := escapeLoggingCommand("100%\r\nok")
+	expected := "100%AZP25%0D%0Aok"
+	if actual != expected {
+		t.Errorf("expected %s, but received: %s", expected, actual)
+	}
+}
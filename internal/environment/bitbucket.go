@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// dotenv file written to the clone directory, declare it as a step artifact to share outputs with later steps
+const BitbucketOutputFile = "tfci.env"
+
+// Sourced from: https://support.atlassian.com/bitbucket-cloud/docs/variables-and-secrets/
+type BitbucketContext struct {
+	// The unique identifier for a build. It increments with each build and can be used to create unique artifact names.
+	buildNumber string
+	// The commit hash of a commit that kicked off the build.
+	commit string
+	// The UUID of the user who triggered the pipeline step.
+	stepTriggererUUID string
+	// The source branch. This value is only available on branches.
+	branch string
+	// The absolute path of the directory that the repository is cloned into within the Docker container.
+	cloneDir string
+	// The pull request ID, only available on a pull request triggered build.
+	prID string
+	// The pull request destination branch, used in combination with BITBUCKET_BRANCH.
+	prDestinationBranch string
+	// The URL for the origin, for example: http://bitbucket.org/<workspace>/<repo>
+	gitHTTPOrigin string
+	// The map containing output data
+	output OutputMap
+}
+
+func (bb *BitbucketContext) ID() string {
+	return fmt.Sprintf("bb-%s", bb.buildNumber)
+}
+
+func (bb *BitbucketContext) SHA() string {
+	return bb.commit
+}
+
+func (bb *BitbucketContext) SHAShort() string {
+	if len(bb.commit) > 7 {
+		return bb.commit[:7]
+	}
+	return bb.commit
+}
+
+func (bb *BitbucketContext) Author() string {
+	return bb.stepTriggererUUID
+}
+
+func (bb *BitbucketContext) WriteDir() string {
+	return bb.cloneDir
+}
+
+func (bb *BitbucketContext) SetOutput(output OutputMap) {
+	bb.output = output
+}
+
+func (bb *BitbucketContext) CloseOutput() (err error) {
+	log.Printf("[DEBUG] Bitbucket flushing output")
+
+	file, err := os.Create(filepath.Join(bb.WriteDir(), BitbucketOutputFile))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var lines []string
+	for k, v := range bb.output {
+		// dotenv does not support multiline values, store them as separate artifacts
+		if v.MultiLine() {
+			if err = writeArtifact(bb.WriteDir(), "tfci", k, v.String()); err != nil {
+				return
+			}
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s=%s", k, v.String()))
+	}
+
+	if _, err = file.WriteString(strings.Join(lines, EOF)); err != nil {
+		return
+	}
+
+	// reset output
+	bb.output = make(map[string]OutputWriter)
+
+	return
+}
+
+func (bb *BitbucketContext) Ref() string {
+	return bb.branch
+}
+
+func (bb *BitbucketContext) PullRequest() *PullRequest {
+	number, err := strconv.Atoi(bb.prID)
+	if err != nil {
+		return nil
+	}
+	pr := &PullRequest{
+		Number:  number,
+		HeadRef: bb.branch,
+		BaseRef: bb.prDestinationBranch,
+		HeadSHA: bb.commit,
+	}
+	if bb.gitHTTPOrigin != "" {
+		pr.URL = fmt.Sprintf("%s/pull-requests/%d", bb.RepositoryURL(), number)
+	}
+	return pr
+}
+
+func (bb *BitbucketContext) ServerURL() string {
+	return "https://bitbucket.org"
+}
+
+func (bb *BitbucketContext) RepositoryURL() string {
+	return strings.Replace(bb.gitHTTPOrigin, "http://", "https://", 1)
+}
+
+func (bb *BitbucketContext) JobURL() string {
+	if bb.gitHTTPOrigin == "" || bb.buildNumber == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/pipelines/results/%s", bb.RepositoryURL(), bb.buildNumber)
+}
+
+func newBitbucketContext(getenv GetEnv) *BitbucketContext {
+	return &BitbucketContext{
+		buildNumber:         getenv("BITBUCKET_BUILD_NUMBER"),
+		commit:              getenv("BITBUCKET_COMMIT"),
+		stepTriggererUUID:   getenv("BITBUCKET_STEP_TRIGGERER_UUID"),
+		branch:              getenv("BITBUCKET_BRANCH"),
+		cloneDir:            getenv("BITBUCKET_CLONE_DIR"),
+		prID:                getenv("BITBUCKET_PR_ID"),
+		prDestinationBranch: getenv("BITBUCKET_PR_DESTINATION_BRANCH"),
+		gitHTTPOrigin:       getenv("BITBUCKET_GIT_HTTP_ORIGIN"),
+		output:              make(map[string]OutputWriter),
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getBitbucketEnvMock(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"BITBUCKET_BUILD_NUMBER":        "42",
+		"BITBUCKET_COMMIT":              randomSha(t),
+		"BITBUCKET_STEP_TRIGGERER_UUID": "{a1b2c3}",
+		"BITBUCKET_CLONE_DIR":           t.TempDir(),
+	}
+}
+
+func Test_BitbucketContext(t *testing.T) {
+	env := getBitbucketEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	bitbucket := newBitbucketContext(getenv)
+
+	if actualID := bitbucket.ID(); actualID != "bb-42" {
+		t.Errorf("expected %s, but received: %s", "bb-42", actualID)
+	}
+
+	if actualSHA := bitbucket.SHA(); actualSHA != env["BITBUCKET_COMMIT"] {
+		t.Errorf("expected %s, but received: %s", env["BITBUCKET_COMMIT"], actualSHA)
+	}
+
+	if actualAuthor := bitbucket.Author(); actualAuthor != env["BITBUCKET_STEP_TRIGGERER_UUID"] {
+		t.Errorf("expected %s, but received: %s", env["BITBUCKET_STEP_TRIGGERER_UUID"], actualAuthor)
+	}
+}
+
+func Test_BitbucketOutput(t *testing.T) {
+	env := getBitbucketEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	bitbucket := newBitbucketContext(getenv)
+
+	bitbucket.SetOutput(OutputMap{
+		"k1":      &testOutput{val: "v1"},
+		"k2":      &testOutput{val: "v2"},
+		"payload": &testOutput{val: `{"pk": "pv"}`, multiLine: true},
+	})
+
+	if err := bitbucket.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(env["BITBUCKET_CLONE_DIR"], BitbucketOutputFile))
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	envs := make(map[string]string)
+	for _, l := range strings.Split(string(contents), "\n") {
+		kv := strings.Split(l, "=")
+		if len(kv) != 2 {
+			t.Fatalf("line %s was not in correct dotenv format", l)
+		}
+		envs[kv[0]] = kv[1]
+	}
+
+	if envs["k1"] != "v1" || envs["k2"] != "v2" {
+		t.Errorf("unexpected dotenv contents: %v", envs)
+	}
+
+	if _, exists := envs["payload"]; exists {
+		t.Errorf("multiline value should not be stored in dotenv file")
+	}
+
+	payload, err := os.ReadFile(filepath.Join(env["BITBUCKET_CLONE_DIR"], generateArtifactFileName("json", "tfci", "payload")))
+	if err != nil {
+		t.Fatalf("artifact read error: %v", err)
+	}
+	if string(payload) != `{"pk": "pv"}` {
+		t.Errorf("unexpected artifact contents: %q", string(payload))
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Sourced from: https://circleci.com/docs/variables/#built-in-environment-variables
+type CircleCIContext struct {
+	// The number of the current job. Job numbers are unique for each job.
+	buildNum string
+	// A unique identifier for the workflow instance of the current job.
+	workflowId string
+	// The name of the current job.
+	job string
+	// The URL for the current job on CircleCI.
+	buildURL string
+	// The SHA1 hash of the last commit of the current build.
+	sha1 string
+	// The GitHub or Bitbucket username of the user who triggered the pipeline.
+	username string
+	// The name of the Git branch currently being built.
+	branch string
+	// Path to the file sourced by each step, exported variables are available to subsequent steps.
+	bashEnv string
+	// The URL of the associated pull request. If there are multiple associated pull requests, one URL is randomly chosen.
+	pullRequest string
+	// The URL of your GitHub or Bitbucket repository.
+	repositoryURL string
+	// The map containing output data
+	output OutputMap
+}
+
+func (cci *CircleCIContext) ID() string {
+	return fmt.Sprintf("cci-%s-%s", cci.workflowId, cci.buildNum)
+}
+
+func (cci *CircleCIContext) SHA() string {
+	return cci.sha1
+}
+
+func (cci *CircleCIContext) SHAShort() string {
+	if len(cci.sha1) > 7 {
+		return cci.sha1[:7]
+	}
+	return cci.sha1
+}
+
+func (cci *CircleCIContext) Author() string {
+	return cci.username
+}
+
+func (cci *CircleCIContext) Ref() string {
+	return cci.branch
+}
+
+// only the pull request URL is exported, the number is resolved from its last path segment
+func (cci *CircleCIContext) PullRequest() *PullRequest {
+	if cci.pullRequest == "" {
+		return nil
+	}
+	number, err := strconv.Atoi(path.Base(cci.pullRequest))
+	if err != nil {
+		return nil
+	}
+	return &PullRequest{
+		Number:  number,
+		URL:     cci.pullRequest,
+		HeadRef: cci.branch,
+		HeadSHA: cci.sha1,
+	}
+}
+
+func (cci *CircleCIContext) ServerURL() string {
+	return serverWebURL(cci.RepositoryURL())
+}
+
+// repository URL is exported as a git remote and converted to a web URL
+func (cci *CircleCIContext) RepositoryURL() string {
+	return repositoryWebURL(cci.repositoryURL)
+}
+
+func (cci *CircleCIContext) JobURL() string {
+	return cci.buildURL
+}
+
+func (cci *CircleCIContext) WriteDir() string {
+	// steps run from the job's working_directory, artifacts are stored relative to it
+	return ""
+}
+
+func (cci *CircleCIContext) SetOutput(output OutputMap) {
+	cci.output = output
+}
+
+func (cci *CircleCIContext) CloseOutput() (err error) {
+	log.Printf("[DEBUG] CircleCI flushing output")
+
+	file, err := os.OpenFile(cci.bashEnv, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var lines []string
+	for k, v := range cci.output {
+		if v.MultiLine() {
+			if err = writeArtifact(cci.WriteDir(), cci.job, k, v.String()); err != nil {
+				return
+			}
+			continue
+		}
+
+		lines = append(lines, shellExport(k, v.String()))
+	}
+
+	if _, err = file.WriteString(strings.Join(lines, EOF) + EOF); err != nil {
+		return
+	}
+
+	// reset output
+	cci.output = make(map[string]OutputWriter)
+
+	return
+}
+
+func shellExport(k string, v string) string {
+	return fmt.Sprintf("export %s=%s", k, shellQuote(v))
+}
+
+// single quote value so it is not expanded when the file is sourced
+func shellQuote(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
+func newCircleCIContext(getenv GetEnv) *CircleCIContext {
+	return &CircleCIContext{
+		buildNum:      getenv("CIRCLE_BUILD_NUM"),
+		workflowId:    getenv("CIRCLE_WORKFLOW_ID"),
+		job:           getenv("CIRCLE_JOB"),
+		buildURL:      getenv("CIRCLE_BUILD_URL"),
+		sha1:          getenv("CIRCLE_SHA1"),
+		username:      getenv("CIRCLE_USERNAME"),
+		branch:        getenv("CIRCLE_BRANCH"),
+		bashEnv:       getenv("BASH_ENV"),
+		pullRequest:   getenv("CIRCLE_PULL_REQUEST"),
+		repositoryURL: getenv("CIRCLE_REPOSITORY_URL"),
+		output:        make(map[string]OutputWriter),
+	}
+}
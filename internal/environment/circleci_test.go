@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getCircleCIEnvMock(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"CIRCLECI":           "true",
+		"CIRCLE_BUILD_NUM":   "7",
+		"CIRCLE_WORKFLOW_ID": "wf-123",
+		"CIRCLE_JOB":         "plan",
+		"CIRCLE_SHA1":        randomSha(t),
+		"CIRCLE_USERNAME":    "octocat",
+		"BASH_ENV":           filepath.Join(t.TempDir(), "bash_env"),
+	}
+}
+
+func Test_CircleCIContext(t *testing.T) {
+	env := getCircleCIEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	circle := newCircleCIContext(getenv)
+
+	if actualID := circle.ID(); actualID != "cci-wf-123-7" {
+		t.Errorf("expected %s, but received: %s", "cci-wf-123-7", actualID)
+	}
+
+	if actualSHA := circle.SHA(); actualSHA != env["CIRCLE_SHA1"] {
+		t.Errorf("expected %s, but received: %s", env["CIRCLE_SHA1"], actualSHA)
+	}
+
+	if actualAuthor := circle.Author(); actualAuthor != env["CIRCLE_USERNAME"] {
+		t.Errorf("expected %s, but received: %s", env["CIRCLE_USERNAME"], actualAuthor)
+	}
+}
+
+func Test_CircleCIOutput(t *testing.T) {
+	env := getCircleCIEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	circle := newCircleCIContext(getenv)
+
+	circle.SetOutput(OutputMap{
+		"run_id":      &testOutput{val: "run-abc123"},
+		"run_message": &testOutput{val: "it's a $message"},
+		"payload":     &testOutput{val: `{"pk": "pv"}`, multiLine: true},
+	})
+
+	if err := circle.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	contents, err := os.ReadFile(env["BASH_ENV"])
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	if strings.Contains(string(contents), "payload") {
+		t.Errorf("multiline value should not be exported: %q", string(contents))
+	}
+
+	artifact := generateArtifactFileName("json", env["CIRCLE_JOB"], "payload")
+	t.Cleanup(func() {
+		os.Remove(artifact)
+	})
+	if _, err := os.Stat(artifact); err != nil {
+		t.Fatalf("expected artifact %s to be written: %v", artifact, err)
+	}
+
+	// source exported file and ensure values survive shell quoting
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	out, err := exec.Command("sh", "-c", ". "+env["BASH_ENV"]+` && printf '%s|%s' "$run_id" "$run_message"`).Output()
+	if err != nil {
+		t.Fatalf("error sourcing exported values: %v", err)
+	}
+	if string(out) != "run-abc123|it's a $message" {
+		t.Errorf("unexpected sourced values: %q", string(out))
+	}
+}
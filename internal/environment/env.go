@@ -4,17 +4,28 @@
 package environment
 
 import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 )
 
 type PlatformType string
 
 const (
-	GitLab PlatformType = "GitLab"
-	GitHub PlatformType = "GitHub"
-	Other  PlatformType = "Other"
+	GitLab      PlatformType = "GitLab"
+	GitHub      PlatformType = "GitHub"
+	AzureDevOps PlatformType = "AzureDevOps"
+	Bitbucket   PlatformType = "Bitbucket"
+	CircleCI    PlatformType = "CircleCI"
+	Jenkins     PlatformType = "Jenkins"
+	TeamCity    PlatformType = "TeamCity"
+	Generic     PlatformType = "Generic"
+	Other       PlatformType = "Other"
 )
 
 var (
@@ -73,11 +84,77 @@ type Common interface {
 	SHA() string
 	SHAShort() string
 	Author() string
+	// branch or tag name that triggered the pipeline, the source branch for pull requests
+	Ref() string
+	// pull request the pipeline runs for, nil when not triggered by a pull request
+	PullRequest() *PullRequest
+	// base URL of the source control server, eg. https://github.com
+	ServerURL() string
+	// web URL of the repository
+	RepositoryURL() string
+	// web URL of the pipeline job, empty when the platform does not expose it
+	JobURL() string
 	WriteDir() string // where to store tmp files
 	SetOutput(output OutputMap)
 	CloseOutput() error
 }
 
+// PullRequest (or merge request) details, values not provided by a platform are left empty
+type PullRequest struct {
+	Number  int
+	Title   string
+	URL     string
+	HeadRef string
+	BaseRef string
+	// latest commit of the source branch
+	HeadSHA string
+	// commit of the merge result tested by the pipeline
+	MergeCommitSHA string
+	Labels         []string
+}
+
+// optional interface for platforms that can render a markdown report for the job
+type SummaryWriter interface {
+	WriteSummary(markdown string) error
+}
+
+// Diagnostic is a problem reported by Terraform, located in a file of the repository when a source range is known
+type Diagnostic struct {
+	// one of: error, warning
+	Severity string
+	Summary  string
+	Detail   string
+	// path relative to the repository root, empty when the diagnostic is not tied to a file
+	Filename  string
+	Line      int
+	EndLine   int
+	Column    int
+	EndColumn int
+}
+
+// optional interface for platforms that can annotate source files with diagnostics
+type DiagnosticReporter interface {
+	ReportDiagnostics(diags []*Diagnostic) error
+}
+
+// optional interface for platforms that read commands, eg. output variables, from the job log
+// commands are redirected to stderr with `-json` so stdout only contains the json result
+type CommandWriter interface {
+	SetCommandWriter(w io.Writer)
+}
+
+// PlanReport contains the resource change counts of a plan
+type PlanReport struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// optional interface for platforms that can render plan results natively
+type PlanReporter interface {
+	ReportPlan(report *PlanReport) error
+}
+
 func (c *CI) initialize() {
 	ci, _ := strconv.ParseBool(c.getenv("CI"))
 	c.CI = ci
@@ -93,6 +170,52 @@ func (c *CI) initialize() {
 		return
 	}
 
+	if tfBuild, _ := strconv.ParseBool(c.getenv("TF_BUILD")); tfBuild {
+		c.PlatformType = AzureDevOps
+		c.Context = newAzureDevOpsContext(c.getenv)
+		return
+	}
+
+	if c.getenv("BITBUCKET_BUILD_NUMBER") != "" {
+		c.PlatformType = Bitbucket
+		c.Context = newBitbucketContext(c.getenv)
+		return
+	}
+
+	if c.getenv("CIRCLECI") == "true" {
+		c.PlatformType = CircleCI
+		c.Context = newCircleCIContext(c.getenv)
+		return
+	}
+
+	if c.getenv("JENKINS_URL") != "" {
+		c.PlatformType = Jenkins
+		c.Context = newJenkinsContext(c.getenv)
+		return
+	}
+
+	if c.getenv("TEAMCITY_VERSION") != "" {
+		c.PlatformType = TeamCity
+		c.Context = newTeamCityContext(c.getenv)
+		return
+	}
+
+	// fallback to user declared platform mapping when no built-in platform is detected
+	cfg, err := loadPlatformConfig(c.getenv)
+	if err != nil {
+		log.Printf("[ERROR] invalid platform configuration: %s", err)
+	}
+	if cfg != nil {
+		generic, err := newGenericContext(c.getenv, cfg)
+		if err != nil {
+			log.Printf("[ERROR] invalid platform configuration: %s", err)
+		} else {
+			c.PlatformType = Generic
+			c.Context = generic
+			return
+		}
+	}
+
 	c.PlatformType = Other
 }
 
@@ -105,3 +228,21 @@ func NewCIContext() *CI {
 	})
 	return &envCtx
 }
+
+// converts a git remote, eg. git@github.com:org/repo.git, to the web URL of the repository
+func repositoryWebURL(remote string) string {
+	repo := strings.TrimSuffix(remote, ".git")
+	if strings.HasPrefix(repo, "git@") {
+		repo = "https://" + strings.Replace(strings.TrimPrefix(repo, "git@"), ":", "/", 1)
+	}
+	return repo
+}
+
+// returns scheme and host of a repository web URL
+func serverWebURL(repositoryURL string) string {
+	u, err := url.Parse(repositoryURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"testing"
+)
+
+func TestCI_Initialize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected PlatformType
+	}{
+		{
+			name:     "github",
+			env:      map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"},
+			expected: GitHub,
+		},
+		{
+			name:     "gitlab",
+			env:      map[string]string{"CI": "true", "GITLAB_CI": "true"},
+			expected: GitLab,
+		},
+		{
+			name:     "azure-devops",
+			env:      map[string]string{"TF_BUILD": "True"},
+			expected: AzureDevOps,
+		},
+		{
+			name:     "bitbucket",
+			env:      map[string]string{"CI": "true", "BITBUCKET_BUILD_NUMBER": "1"},
+			expected: Bitbucket,
+		},
+		{
+			name:     "circleci",
+			env:      map[string]string{"CI": "true", "CIRCLECI": "true"},
+			expected: CircleCI,
+		},
+		{
+			name:     "jenkins",
+			env:      map[string]string{"JENKINS_URL": "https://jenkins.example.com/"},
+			expected: Jenkins,
+		},
+		{
+			name:     "teamcity",
+			env:      map[string]string{"TEAMCITY_VERSION": "2024.03"},
+			expected: TeamCity,
+		},
+		{
+			name:     "generic",
+			env:      map[string]string{"CI": "true", "TFCI_PLATFORM_NAME": "drone", "TFCI_PLATFORM_SHA_VAR": "DRONE_COMMIT_SHA"},
+			expected: Generic,
+		},
+		{
+			name:     "generic-invalid",
+			env:      map[string]string{"TFCI_PLATFORM_OUTPUT_FORMAT": "xml"},
+			expected: Other,
+		},
+		{
+			name:     "other",
+			env:      map[string]string{},
+			expected: Other,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ci := &CI{
+				getenv: func(k string) string {
+					return tc.env[k]
+				},
+			}
+			ci.initialize()
+
+			if ci.PlatformType != tc.expected {
+				t.Errorf("expected %s, but received: %s", tc.expected, ci.PlatformType)
+			}
+
+			if tc.expected == Other && ci.Context != nil {
+				t.Errorf("expected nil context for %s", tc.expected)
+			}
+
+			if tc.expected != Other && ci.Context == nil {
+				t.Errorf("expected context for %s", tc.expected)
+			}
+		})
+	}
+}
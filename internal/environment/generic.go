@@ -0,0 +1,295 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	envPlatformConfig         = "TFCI_PLATFORM_CONFIG"
+	envPlatformName           = "TFCI_PLATFORM_NAME"
+	envPlatformIDVar          = "TFCI_PLATFORM_ID_VAR"
+	envPlatformSHAVar         = "TFCI_PLATFORM_SHA_VAR"
+	envPlatformAuthorVar      = "TFCI_PLATFORM_AUTHOR_VAR"
+	envPlatformRefVar         = "TFCI_PLATFORM_REF_VAR"
+	envPlatformWriteDir       = "TFCI_PLATFORM_WRITE_DIR"
+	envPlatformOutputFormat   = "TFCI_PLATFORM_OUTPUT_FORMAT"
+	envPlatformOutputFile     = "TFCI_PLATFORM_OUTPUT_FILE"
+	envPlatformOutputTemplate = "TFCI_PLATFORM_OUTPUT_TEMPLATE"
+)
+
+// default file names when an output file is not configured
+var defaultOutputFiles = map[OutputFormat]string{
+	OutputFormatDotenv: "tfci.env",
+	OutputFormatJSON:   "tfci.json",
+	OutputFormatShell:  "tfci.sh",
+}
+
+// PlatformConfig declares how tfci reads CI metadata and writes outputs for a platform
+// without built-in support. It is read from the json file referenced by `TFCI_PLATFORM_CONFIG`,
+// any `TFCI_PLATFORM_*` environment variable overrides the matching value.
+type PlatformConfig struct {
+	// name of the platform, used as an identifier prefix
+	Name string `json:"name"`
+	// names of the environment variables holding each value
+	IDVar     string `json:"id_var"`
+	SHAVar    string `json:"sha_var"`
+	AuthorVar string `json:"author_var"`
+	RefVar    string `json:"ref_var"`
+	// directory for tmp files and artifacts, environment variables are expanded
+	WriteDir string `json:"write_dir"`
+	// one of: dotenv, json, shell, template
+	OutputFormat OutputFormat `json:"output_format"`
+	// file that outputs are written to, environment variables are expanded
+	OutputFile string `json:"output_file"`
+	// text/template executed for each output and written to stdout, with {{.Name}}, {{.Value}} and {{.MultiLine}}
+	OutputTemplate string `json:"output_template"`
+}
+
+func (p *PlatformConfig) Validate() error {
+	switch p.OutputFormat {
+	case OutputFormatDotenv, OutputFormatJSON, OutputFormatShell:
+	case OutputFormatTemplate:
+		if p.OutputTemplate == "" {
+			return fmt.Errorf("output format %q requires an output template", p.OutputFormat)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", p.OutputFormat)
+	}
+	return nil
+}
+
+// resolves platform configuration, returns nil when tfci has not been configured for a generic platform
+func loadPlatformConfig(getenv GetEnv) (*PlatformConfig, error) {
+	cfg := &PlatformConfig{}
+	configured := false
+
+	if path := getenv(envPlatformConfig); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading platform config %q: %w", path, err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("error parsing platform config %q: %w", path, err)
+		}
+		configured = true
+	}
+
+	overrides := map[string]*string{
+		envPlatformName:           &cfg.Name,
+		envPlatformIDVar:          &cfg.IDVar,
+		envPlatformSHAVar:         &cfg.SHAVar,
+		envPlatformAuthorVar:      &cfg.AuthorVar,
+		envPlatformRefVar:         &cfg.RefVar,
+		envPlatformWriteDir:       &cfg.WriteDir,
+		envPlatformOutputFile:     &cfg.OutputFile,
+		envPlatformOutputTemplate: &cfg.OutputTemplate,
+	}
+	for k, field := range overrides {
+		if v := getenv(k); v != "" {
+			*field = v
+			configured = true
+		}
+	}
+	if v := getenv(envPlatformOutputFormat); v != "" {
+		cfg.OutputFormat = OutputFormat(v)
+		configured = true
+	}
+
+	if !configured {
+		return nil, nil
+	}
+
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = OutputFormatDotenv
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+type GenericContext struct {
+	name     string
+	id       string
+	sha      string
+	author   string
+	ref      string
+	writeDir string
+	// configured sink for outputs
+	format     OutputFormat
+	outputFile string
+	tmpl       *template.Template
+	// where templated outputs are written
+	stdout io.Writer
+	// The map containing output data
+	output OutputMap
+}
+
+func (g *GenericContext) ID() string {
+	if g.name == "" {
+		return g.id
+	}
+	return fmt.Sprintf("%s-%s", g.name, g.id)
+}
+
+func (g *GenericContext) SHA() string {
+	return g.sha
+}
+
+func (g *GenericContext) SHAShort() string {
+	if len(g.sha) > 7 {
+		return g.sha[:7]
+	}
+	return g.sha
+}
+
+func (g *GenericContext) Author() string {
+	return g.author
+}
+
+// The branch or tag name resolved from the configured ref variable
+func (g *GenericContext) Ref() string {
+	return g.ref
+}
+
+// pull requests and repository details are not mapped for generic platforms
+func (g *GenericContext) PullRequest() *PullRequest {
+	return nil
+}
+
+func (g *GenericContext) ServerURL() string {
+	return ""
+}
+
+func (g *GenericContext) RepositoryURL() string {
+	return ""
+}
+
+func (g *GenericContext) JobURL() string {
+	return ""
+}
+
+func (g *GenericContext) WriteDir() string {
+	return g.writeDir
+}
+
+func (g *GenericContext) SetOutput(output OutputMap) {
+	g.output = output
+}
+
+func (g *GenericContext) CloseOutput() error {
+	log.Printf("[DEBUG] %s flushing output with format: %s", g.name, g.format)
+
+	var err error
+	switch g.format {
+	case OutputFormatTemplate:
+		err = g.writeTemplate()
+	case OutputFormatJSON:
+		var content string
+		if content, err = formatJSON(g.output); err == nil {
+			err = os.WriteFile(g.outputFile, []byte(content), 0644)
+		}
+	case OutputFormatShell:
+		err = os.WriteFile(g.outputFile, []byte(formatShell(g.output)+EOF), 0644)
+	default:
+		err = g.writeDotenv()
+	}
+	if err != nil {
+		return err
+	}
+
+	// reset output
+	g.output = make(map[string]OutputWriter)
+
+	return nil
+}
+
+func (g *GenericContext) writeDotenv() error {
+	// dotenv does not support multiline values, store them as separate artifacts
+	for k, v := range g.output {
+		if v.MultiLine() {
+			if err := writeArtifact(g.WriteDir(), "tfci", k, v.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return os.WriteFile(g.outputFile, []byte(formatDotenv(g.output)), 0644)
+}
+
+// sets where templated outputs are written, defaults to stdout
+func (g *GenericContext) SetCommandWriter(w io.Writer) {
+	g.stdout = w
+}
+
+func (g *GenericContext) writeTemplate() error {
+	for _, k := range sortedKeys(g.output) {
+		v := g.output[k]
+		var b strings.Builder
+		err := g.tmpl.Execute(&b, struct {
+			Name      string
+			Value     string
+			MultiLine bool
+		}{k, v.String(), v.MultiLine()})
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(g.stdout, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newGenericContext(getenv GetEnv, cfg *PlatformConfig) (*GenericContext, error) {
+	lookup := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		return getenv(name)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = "generic"
+	}
+
+	g := &GenericContext{
+		name:     name,
+		id:       lookup(cfg.IDVar),
+		sha:      lookup(cfg.SHAVar),
+		author:   lookup(cfg.AuthorVar),
+		ref:      lookup(cfg.RefVar),
+		writeDir: os.Expand(cfg.WriteDir, getenv),
+		format:   cfg.OutputFormat,
+		stdout:   os.Stdout,
+		output:   make(map[string]OutputWriter),
+	}
+
+	if g.format == OutputFormatTemplate {
+		tmpl, err := template.New(name).Parse(cfg.OutputTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing output template: %w", err)
+		}
+		g.tmpl = tmpl
+		return g, nil
+	}
+
+	g.outputFile = os.Expand(cfg.OutputFile, getenv)
+	if g.outputFile == "" {
+		g.outputFile = filepath.Join(g.writeDir, defaultOutputFiles[g.format])
+	}
+
+	return g, nil
+}
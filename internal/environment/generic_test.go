@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getGenericEnvMock(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"TFCI_PLATFORM_NAME":       "drone",
+		"TFCI_PLATFORM_ID_VAR":     "DRONE_BUILD_NUMBER",
+		"TFCI_PLATFORM_SHA_VAR":    "DRONE_COMMIT_SHA",
+		"TFCI_PLATFORM_AUTHOR_VAR": "DRONE_COMMIT_AUTHOR",
+		"TFCI_PLATFORM_REF_VAR":    "DRONE_BRANCH",
+		"TFCI_PLATFORM_WRITE_DIR":  t.TempDir(),
+		"DRONE_BUILD_NUMBER":       "12",
+		"DRONE_COMMIT_SHA":         randomSha(t),
+		"DRONE_COMMIT_AUTHOR":      "octocat",
+		"DRONE_BRANCH":             "main",
+	}
+}
+
+func testGenericContext(t *testing.T, env map[string]string) *GenericContext {
+	t.Helper()
+	getenv := func(key string) string {
+		return env[key]
+	}
+	cfg, err := loadPlatformConfig(getenv)
+	if err != nil {
+		t.Fatalf("error loading platform config: %s", err)
+	}
+	if cfg == nil {
+		t.Fatalf("expected platform config to be loaded")
+	}
+	generic, err := newGenericContext(getenv, cfg)
+	if err != nil {
+		t.Fatalf("error creating generic context: %s", err)
+	}
+	return generic
+}
+
+func testGenericOutput() OutputMap {
+	return OutputMap{
+		"run_id":  &testOutput{val: "run-abc123"},
+		"status":  &testOutput{val: "Success"},
+		"payload": &testOutput{val: `{"pk": "pv"}`, multiLine: true},
+	}
+}
+
+func Test_GenericContext(t *testing.T) {
+	env := getGenericEnvMock(t)
+	generic := testGenericContext(t, env)
+
+	if actualID := generic.ID(); actualID != "drone-12" {
+		t.Errorf("expected %s, but received: %s", "drone-12", actualID)
+	}
+
+	if actualSHA := generic.SHA(); actualSHA != env["DRONE_COMMIT_SHA"] {
+		t.Errorf("expected %s, but received: %s", env["DRONE_COMMIT_SHA"], actualSHA)
+	}
+
+	if actualAuthor := generic.Author(); actualAuthor != "octocat" {
+		t.Errorf("expected %s, but received: %s", "octocat", actualAuthor)
+	}
+
+	if actualRef := generic.Ref(); actualRef != "main" {
+		t.Errorf("expected %s, but received: %s", "main", actualRef)
+	}
+}
+
+func Test_GenericConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "platform.json")
+	config := `{
+		"name": "woodpecker",
+		"id_var": "CI_PIPELINE_NUMBER",
+		"sha_var": "CI_COMMIT_SHA",
+		"output_format": "json",
+		"output_file": "${CI_WORKSPACE}/outputs.json"
+	}`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatalf("error writing config: %s", err)
+	}
+
+	env := map[string]string{
+		"TFCI_PLATFORM_CONFIG": path,
+		// environment variables take precedence over the config file
+		"TFCI_PLATFORM_SHA_VAR":  "CI_COMMIT_SHA_OVERRIDE",
+		"CI_PIPELINE_NUMBER":     "3",
+		"CI_COMMIT_SHA":          "abc",
+		"CI_COMMIT_SHA_OVERRIDE": "def",
+		"CI_WORKSPACE":           dir,
+	}
+	generic := testGenericContext(t, env)
+
+	if actualID := generic.ID(); actualID != "woodpecker-3" {
+		t.Errorf("expected %s, but received: %s", "woodpecker-3", actualID)
+	}
+
+	if actualSHA := generic.SHA(); actualSHA != "def" {
+		t.Errorf("expected %s, but received: %s", "def", actualSHA)
+	}
+
+	generic.SetOutput(testGenericOutput())
+	if err := generic.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, "outputs.json"))
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+	var outputs map[string]string
+	if err := json.Unmarshal(contents, &outputs); err != nil {
+		t.Fatalf("error parsing json output: %s", err)
+	}
+	if outputs["run_id"] != "run-abc123" || outputs["payload"] != `{"pk": "pv"}` {
+		t.Errorf("unexpected json output: %v", outputs)
+	}
+}
+
+func Test_GenericOutputDotenv(t *testing.T) {
+	env := getGenericEnvMock(t)
+	generic := testGenericContext(t, env)
+
+	generic.SetOutput(testGenericOutput())
+	if err := generic.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	writeDir := env["TFCI_PLATFORM_WRITE_DIR"]
+	contents, err := os.ReadFile(filepath.Join(writeDir, "tfci.env"))
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+	expected := "run_id=run-abc123\nstatus=Success"
+	if string(contents) != expected {
+		t.Errorf("expected %q, but received: %q", expected, string(contents))
+	}
+
+	if _, err := os.Stat(filepath.Join(writeDir, generateArtifactFileName("json", "tfci", "payload"))); err != nil {
+		t.Errorf("expected multiline artifact to be written: %s", err)
+	}
+}
+
+func Test_GenericOutputShell(t *testing.T) {
+	env := getGenericEnvMock(t)
+	env["TFCI_PLATFORM_OUTPUT_FORMAT"] = "shell"
+	generic := testGenericContext(t, env)
+
+	generic.SetOutput(testGenericOutput())
+	if err := generic.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(env["TFCI_PLATFORM_WRITE_DIR"], "tfci.sh"))
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+	if !strings.Contains(string(contents), "export run_id='run-abc123'") {
+		t.Errorf("unexpected shell output: %q", string(contents))
+	}
+}
+
+func Test_GenericOutputTemplate(t *testing.T) {
+	env := getGenericEnvMock(t)
+	env["TFCI_PLATFORM_OUTPUT_FORMAT"] = "template"
+	env["TFCI_PLATFORM_OUTPUT_TEMPLATE"] = `{{if not .MultiLine}}::output {{.Name}}={{.Value}}{{end}}`
+	generic := testGenericContext(t, env)
+	stdout := new(bytes.Buffer)
+	generic.SetCommandWriter(stdout)
+
+	generic.SetOutput(testGenericOutput())
+	if err := generic.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "::output run_id=run-abc123\n::output status=Success") {
+		t.Errorf("unexpected templated output: %q", stdout.String())
+	}
+}
+
+func Test_GenericConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       PlatformConfig
+		expectErr bool
+	}{
+		{name: "dotenv", cfg: PlatformConfig{OutputFormat: OutputFormatDotenv}},
+		{name: "template-without-template", cfg: PlatformConfig{OutputFormat: OutputFormatTemplate}, expectErr: true},
+		{name: "unsupported", cfg: PlatformConfig{OutputFormat: "xml"}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
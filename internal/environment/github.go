@@ -4,8 +4,12 @@
 package environment
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -27,10 +31,28 @@ type GitHubContext struct {
 	refName string
 	// The type of ref that triggered the workflow run. Valid values are branch or tag.
 	refType string
+	// The fully-formed ref of the branch or tag that triggered the workflow run. For pull requests, refs/pull/<pr_number>/merge
+	ref string
+	// Returns the API URL. For example: https://api.github.com, GitHub Enterprise Server uses https://<host>/api/v3
+	apiURL string
+	// Token used to call the GitHub API, must be mapped from secrets.GITHUB_TOKEN or a personal access token
+	token string
+	// The URL of the GitHub server. For example: https://github.com
+	serverURL string
+	// The head ref or source branch of the pull request in a workflow run, only set for pull_request or pull_request_target events
+	headRef string
+	// The name of the base ref or target branch of the pull request in a workflow run, only set for pull_request or pull_request_target events
+	baseRef string
+	// pull request parsed from the webhook event payload at GITHUB_EVENT_PATH
+	pullRequest *PullRequest
 	// The path to a temporary directory on the runner. This directory is emptied at the beginning and end of each job. Note that files will not be removed if the runner's user account does not have permission to delete them.
 	runnerTemp string
 	// path to ::set-output
 	githubOutput string
+	// path to the markdown job summary
+	stepSummary string
+	// where workflow commands are written
+	stdout io.Writer
 	// data sent to GITHUB_OUTPUT
 	output OutputMap
 	//
@@ -55,6 +77,49 @@ func (gh *GitHubContext) Author() string {
 	return gh.actor
 }
 
+// the source branch for pull requests, otherwise the branch or tag name that triggered the workflow
+func (gh *GitHubContext) Ref() string {
+	if gh.headRef != "" {
+		return gh.headRef
+	}
+	return gh.refName
+}
+
+func (gh *GitHubContext) PullRequest() *PullRequest {
+	if gh.pullRequest != nil {
+		return gh.pullRequest
+	}
+	// event payload is not available, fallback to the merge ref
+	number := pullRequestNumberFromRef(gh.ref)
+	if number == 0 {
+		return nil
+	}
+	return &PullRequest{
+		Number:  number,
+		HeadRef: gh.headRef,
+		BaseRef: gh.baseRef,
+		URL:     fmt.Sprintf("%s/pull/%d", gh.RepositoryURL(), number),
+	}
+}
+
+func (gh *GitHubContext) ServerURL() string {
+	if gh.serverURL == "" {
+		return "https://github.com"
+	}
+	return gh.serverURL
+}
+
+func (gh *GitHubContext) RepositoryURL() string {
+	return fmt.Sprintf("%s/%s", gh.ServerURL(), gh.repository)
+}
+
+func (gh *GitHubContext) JobURL() string {
+	if gh.runId == "" || gh.repository == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/actions/runs/%s", gh.RepositoryURL(), gh.runId)
+}
+
 func (gh *GitHubContext) WriteDir() string {
 	return gh.runnerTemp
 }
@@ -94,6 +159,170 @@ func (gh *GitHubContext) CloseOutput() (retErr error) {
 	return
 }
 
+// The owner and repository name. For example, octocat/Hello-World
+func (gh *GitHubContext) Repository() string {
+	return gh.repository
+}
+
+func (gh *GitHubContext) APIURL() string {
+	return gh.apiURL
+}
+
+func (gh *GitHubContext) Token() string {
+	return gh.token
+}
+
+// returns the pull request number, 0 when the workflow was not triggered by a pull request
+func (gh *GitHubContext) PullRequestNumber() int {
+	if pr := gh.PullRequest(); pr != nil {
+		return pr.Number
+	}
+	return 0
+}
+
+// resolves number from a pull request merge ref, eg. refs/pull/42/merge
+func pullRequestNumberFromRef(ref string) int {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "refs" || parts[1] != "pull" {
+		return 0
+	}
+	number, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// subset of the webhook payload of the event that triggered the workflow
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads#pull_request
+type gitHubEvent struct {
+	PullRequest *struct {
+		Number         int    `json:"number"`
+		Title          string `json:"title"`
+		HTMLURL        string `json:"html_url"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+		Head           struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"pull_request"`
+}
+
+// reads pull request from the event payload, nil when the event is not related to a pull request
+func readGitHubPullRequest(eventPath string) (*PullRequest, error) {
+	if eventPath == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, err
+	}
+	event := &gitHubEvent{}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	if event.PullRequest == nil {
+		return nil, nil
+	}
+
+	pr := &PullRequest{
+		Number:         event.PullRequest.Number,
+		Title:          event.PullRequest.Title,
+		URL:            event.PullRequest.HTMLURL,
+		HeadRef:        event.PullRequest.Head.Ref,
+		BaseRef:        event.PullRequest.Base.Ref,
+		HeadSHA:        event.PullRequest.Head.SHA,
+		MergeCommitSHA: event.PullRequest.MergeCommitSHA,
+	}
+	for _, label := range event.PullRequest.Labels {
+		pr.Labels = append(pr.Labels, label.Name)
+	}
+	return pr, nil
+}
+
+// appends markdown to the job summary page of the workflow run
+func (gh *GitHubContext) WriteSummary(markdown string) (retErr error) {
+	if gh.stepSummary == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(gh.stepSummary, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			retErr = err
+		}
+	}()
+
+	_, retErr = file.WriteString(markdown + EOF)
+	return
+}
+
+// emits an ::error or ::warning workflow command for each diagnostic, shown as annotations on the pull request diff
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+func (gh *GitHubContext) ReportDiagnostics(diags []*Diagnostic) error {
+	for _, d := range diags {
+		command := "error"
+		if d.Severity == "warning" {
+			command = "warning"
+		}
+
+		props := []string{}
+		if d.Filename != "" {
+			props = append(props, "file="+escapeCommandProperty(d.Filename))
+			if d.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", d.Line))
+			}
+			if d.EndLine > 0 {
+				props = append(props, fmt.Sprintf("endLine=%d", d.EndLine))
+			}
+			// columns are only valid for annotations on a single line
+			if d.Column > 0 && (d.EndLine == 0 || d.EndLine == d.Line) {
+				props = append(props, fmt.Sprintf("col=%d", d.Column))
+				if d.EndColumn > 0 {
+					props = append(props, fmt.Sprintf("endColumn=%d", d.EndColumn))
+				}
+			}
+		}
+		props = append(props, "title="+escapeCommandProperty(d.Summary))
+
+		message := d.Detail
+		if message == "" {
+			message = d.Summary
+		}
+
+		if _, err := fmt.Fprintf(gh.stdout, "::%s %s::%s%s", command, strings.Join(props, ","), escapeCommandData(message), EOF); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sets where workflow commands are written, defaults to stdout
+func (gh *GitHubContext) SetCommandWriter(w io.Writer) {
+	gh.stdout = w
+}
+
+func escapeCommandData(v string) string {
+	v = strings.ReplaceAll(v, "%", "%25")
+	v = strings.ReplaceAll(v, "\r", "%0D")
+	return strings.ReplaceAll(v, "\n", "%0A")
+}
+
+func escapeCommandProperty(v string) string {
+	v = escapeCommandData(v)
+	v = strings.ReplaceAll(v, ":", "%3A")
+	return strings.ReplaceAll(v, ",", "%2C")
+}
+
 func newGitHubContext(getenv GetEnv) *GitHubContext {
 	ghCtx := &GitHubContext{
 		runId:        getenv("GITHUB_RUN_ID"),
@@ -103,10 +332,23 @@ func newGitHubContext(getenv GetEnv) *GitHubContext {
 		repository:   getenv("GITHUB_REPOSITORY"),
 		refName:      getenv("GITHUB_REF_NAME"),
 		refType:      getenv("GITHUB_REF_TYPE"),
+		ref:          getenv("GITHUB_REF"),
+		apiURL:       getenv("GITHUB_API_URL"),
+		token:        getenv("GITHUB_TOKEN"),
+		serverURL:    getenv("GITHUB_SERVER_URL"),
+		headRef:      getenv("GITHUB_HEAD_REF"),
+		baseRef:      getenv("GITHUB_BASE_REF"),
 		githubOutput: getenv("GITHUB_OUTPUT"),
+		stepSummary:  getenv("GITHUB_STEP_SUMMARY"),
 		runnerTemp:   getenv("RUNNER_TEMP"),
+		stdout:       os.Stdout,
 		output:       make(map[string]OutputWriter),
 	}
+	pr, err := readGitHubPullRequest(getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		log.Printf("[ERROR] unable to read GitHub event payload: %s", err)
+	}
+	ghCtx.pullRequest = pr
 	// set random/unique to each github action runner
 	ghCtx.fileDelimeter = fmt.Sprintf("_GH%s%sFD_", ghCtx.runId, ghCtx.runNumber)
 	return ghCtx
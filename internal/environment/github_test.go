@@ -108,3 +108,163 @@ func Test_GitHubContext(t *testing.T) {
 		t.Errorf("expected %s, but received: %s", sha, actualSHA)
 	}
 }
+
+func Test_GitHubSummary(t *testing.T) {
+	env := getEnvMock(t)
+	env["GITHUB_STEP_SUMMARY"] = filepath.Join(t.TempDir(), "step_summary")
+	getenv := func(key string) string {
+		return env[key]
+	}
+	github := newGitHubContext(getenv)
+
+	var _ SummaryWriter = github
+
+	if err := github.WriteSummary("### First"); err != nil {
+		t.Fatalf("error writing summary: %s", err)
+	}
+	if err := github.WriteSummary("### Second"); err != nil {
+		t.Fatalf("error writing summary: %s", err)
+	}
+
+	contents, err := os.ReadFile(env["GITHUB_STEP_SUMMARY"])
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	expected := "### First\n### Second\n"
+	if string(contents) != expected {
+		t.Errorf("expected %q, but received: %q", expected, string(contents))
+	}
+}
+
+func Test_GitHubDiagnostics(t *testing.T) {
+	env := getEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	github := newGitHubContext(getenv)
+	stdout := &strings.Builder{}
+	github.SetCommandWriter(stdout)
+
+	var _ DiagnosticReporter = github
+	var _ CommandWriter = github
+
+	err := github.ReportDiagnostics([]*Diagnostic{
+		{
+			Severity:  "error",
+			Summary:   "Unsupported argument",
+			Detail:    "An argument named \"foo\" is not expected here.\n100% sure",
+			Filename:  "infra/main.tf",
+			Line:      3,
+			EndLine:   3,
+			Column:    3,
+			EndColumn: 6,
+		},
+		{
+			Severity: "warning",
+			Summary:  "Deprecated: use a, b",
+		},
+	})
+	if err != nil {
+		t.Fatalf("error reporting diagnostics: %s", err)
+	}
+
+	expected := "::error file=infra/main.tf,line=3,endLine=3,col=3,endColumn=6,title=Unsupported argument::An argument named \"foo\" is not expected here.%0A100%25 sure\n" +
+		"::warning title=Deprecated%3A use a%2C b::Deprecated: use a, b\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q, but received: %q", expected, stdout.String())
+	}
+}
+
+func Test_GitHubPullRequestNumber(t *testing.T) {
+	testCases := []struct {
+		ref      string
+		expected int
+	}{
+		{ref: "refs/pull/42/merge", expected: 42},
+		{ref: "refs/heads/main", expected: 0},
+		{ref: "refs/pull/abc/merge", expected: 0},
+		{ref: "", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ref, func(t *testing.T) {
+			github := newGitHubContext(func(key string) string {
+				if key == "GITHUB_REF" {
+					return tc.ref
+				}
+				return ""
+			})
+			if github.PullRequestNumber() != tc.expected {
+				t.Errorf("expected %d, but received: %d", tc.expected, github.PullRequestNumber())
+			}
+		})
+	}
+}
+
+func Test_GitHubPullRequestEvent(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	event := `{
+  "action": "synchronize",
+  "pull_request": {
+    "number": 7,
+    "title": "Add networking module",
+    "html_url": "https://github.com/octocat/infra/pull/7",
+    "merge_commit_sha": "8a1f0c3",
+    "head": {"ref": "feature/network", "sha": "5d2e9b1"},
+    "base": {"ref": "main"},
+    "labels": [{"name": "terraform"}, {"name": "network"}]
+  }
+}`
+	if err := os.WriteFile(eventPath, []byte(event), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"GITHUB_EVENT_PATH": eventPath,
+		"GITHUB_REF":        "refs/pull/7/merge",
+		"GITHUB_REF_NAME":   "7/merge",
+		"GITHUB_HEAD_REF":   "feature/network",
+		"GITHUB_REPOSITORY": "octocat/infra",
+	}
+	github := newGitHubContext(func(key string) string {
+		return env[key]
+	})
+
+	pr := github.PullRequest()
+	if pr == nil {
+		t.Fatal("expected pull request, but received nil")
+	}
+	if pr.Number != 7 || pr.Title != "Add networking module" || pr.URL != "https://github.com/octocat/infra/pull/7" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+	if pr.HeadRef != "feature/network" || pr.BaseRef != "main" || pr.HeadSHA != "5d2e9b1" || pr.MergeCommitSHA != "8a1f0c3" {
+		t.Errorf("unexpected pull request refs: %+v", pr)
+	}
+	if strings.Join(pr.Labels, ",") != "terraform,network" {
+		t.Errorf("expected labels terraform,network, but received: %v", pr.Labels)
+	}
+	if github.Ref() != "feature/network" {
+		t.Errorf("expected ref feature/network, but received: %s", github.Ref())
+	}
+	if github.RepositoryURL() != "https://github.com/octocat/infra" {
+		t.Errorf("expected repository url https://github.com/octocat/infra, but received: %s", github.RepositoryURL())
+	}
+
+	// events without a pull request fall back to the ref
+	if err := os.WriteFile(eventPath, []byte(`{"ref": "refs/heads/main"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env["GITHUB_REF"] = "refs/heads/main"
+	env["GITHUB_REF_NAME"] = "main"
+	env["GITHUB_HEAD_REF"] = ""
+	github = newGitHubContext(func(key string) string {
+		return env[key]
+	})
+	if pr := github.PullRequest(); pr != nil {
+		t.Errorf("expected no pull request, but received: %+v", pr)
+	}
+	if github.Ref() != "main" {
+		t.Errorf("expected ref main, but received: %s", github.Ref())
+	}
+}
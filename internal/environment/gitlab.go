@@ -4,12 +4,29 @@
 package environment
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
+// file name of the report that can be declared as a `codequality` artifact
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html
+const GitLabCodeQualityReport = "gl-code-quality-report.json"
+
+// file name of the report that can be declared as a `terraform` artifact, it powers the merge request widget
+// https://docs.gitlab.com/ee/ci/yaml/artifacts_reports.html#artifactsreportsterraform
+const GitLabTerraformReport = "gl-terraform-report.json"
+
+// overrides the directory artifacts and output files are written to,
+// defaults to the current directory (usually $CI_PROJECT_DIR)
+const envArtifactDir = "TFCI_ARTIFACT_DIR"
+
 // Sourced: from https://docs.gitlab.com/ee/ci/variables/predefined_variables.html
 type GitLabContext struct {
 	// The unique ID of build execution in a single executor.
@@ -18,6 +35,8 @@ type GitLabContext struct {
 	concurrentProjectId string
 	// The name of the job being run
 	jobName string
+	// The job details URL.
+	jobURL string
 	// The commit revision the project is built for.
 	commitSHA string
 	//The first eight characters of CI_COMMIT_SHA.
@@ -28,12 +47,40 @@ type GitLabContext struct {
 	commitRefName string
 	// The full commit message.
 	commitMessage string
+	// Directory artifacts are written to, configured with TFCI_ARTIFACT_DIR
+	artifactDir string
+	// The GitLab API v4 root URL.
+	apiURL string
+	// The ID of the current project.
+	projectID string
+	// The HTTP(S) address of the project.
+	projectURL string
+	// The project-level IID (internal ID) of the merge request, only available in merge request pipelines.
+	mergeRequestIID string
+	// The title of the merge request.
+	mergeRequestTitle string
+	// The source branch name of the merge request.
+	mergeRequestSourceBranch string
+	// The target branch name of the merge request.
+	mergeRequestTargetBranch string
+	// The HEAD SHA of the source branch of the merge request, only available in merged results pipelines.
+	mergeRequestSourceBranchSHA string
+	// The event type of the merge request. Can be detached, merged_result or merge_train.
+	mergeRequestEventType string
+	// Comma-separated label names of the merge request.
+	mergeRequestLabels string
+	// The URL of the project of the merge request.
+	mergeRequestProjectURL string
+	// The base URL of the GitLab instance, including protocol and port.
+	serverURL string
+	// Project or personal access token with api scope, configured with GITLAB_TOKEN
+	token string
 	// The map containing output data
 	output OutputMap
 }
 
-func writeArtifact(prefix string, name string, data string) (err error) {
-	file, err := os.Create(generateArtifactFileName("json", prefix, name))
+func writeArtifact(dir string, prefix string, name string, data string) (err error) {
+	file, err := os.Create(filepath.Join(dir, generateArtifactFileName("json", prefix, name)))
 	if err != nil {
 		return
 	}
@@ -62,10 +109,94 @@ func (gl *GitLabContext) Author() string {
 	return gl.commitAuthor
 }
 
-func (gh *GitLabContext) WriteDir() string {
-	// figure out where to store tmp files on gitlab pipeline runner
-	// or let --location= flag dictate
-	return ""
+func (gl *GitLabContext) WriteDir() string {
+	// artifacts must be within the project directory to be uploaded,
+	// relative paths resolve from the job's working directory
+	return gl.artifactDir
+}
+
+// creates the configured artifact directory, if it does not exist yet
+func (gl *GitLabContext) prepareWriteDir() error {
+	if gl.artifactDir == "" {
+		return nil
+	}
+	return os.MkdirAll(gl.artifactDir, 0755)
+}
+
+// the merge request source branch, otherwise the branch or tag name the project is built for
+func (gl *GitLabContext) Ref() string {
+	if gl.mergeRequestSourceBranch != "" {
+		return gl.mergeRequestSourceBranch
+	}
+	return gl.commitRefName
+}
+
+func (gl *GitLabContext) PullRequest() *PullRequest {
+	iid := gl.MergeRequestIID()
+	if iid == 0 {
+		return nil
+	}
+
+	pr := &PullRequest{
+		Number:  iid,
+		Title:   gl.mergeRequestTitle,
+		HeadRef: gl.mergeRequestSourceBranch,
+		BaseRef: gl.mergeRequestTargetBranch,
+		HeadSHA: gl.commitSHA,
+	}
+	// merged results pipelines run for a merge commit of the source and target branch
+	if gl.mergeRequestEventType == "merged_result" || gl.mergeRequestEventType == "merge_train" {
+		pr.HeadSHA = gl.mergeRequestSourceBranchSHA
+		pr.MergeCommitSHA = gl.commitSHA
+	}
+	projectURL := gl.mergeRequestProjectURL
+	if projectURL == "" {
+		projectURL = gl.projectURL
+	}
+	if projectURL != "" {
+		pr.URL = fmt.Sprintf("%s/-/merge_requests/%d", projectURL, iid)
+	}
+	if gl.mergeRequestLabels != "" {
+		pr.Labels = strings.Split(gl.mergeRequestLabels, ",")
+	}
+	return pr
+}
+
+func (gl *GitLabContext) ServerURL() string {
+	return gl.serverURL
+}
+
+func (gl *GitLabContext) RepositoryURL() string {
+	return gl.projectURL
+}
+
+func (gl *GitLabContext) JobURL() string {
+	return gl.jobURL
+}
+
+func (gl *GitLabContext) APIURL() string {
+	return gl.apiURL
+}
+
+func (gl *GitLabContext) ProjectID() string {
+	return gl.projectID
+}
+
+func (gl *GitLabContext) ProjectURL() string {
+	return gl.projectURL
+}
+
+// returns the merge request iid, 0 when the pipeline does not run for a merge request
+func (gl *GitLabContext) MergeRequestIID() int {
+	iid, err := strconv.Atoi(gl.mergeRequestIID)
+	if err != nil {
+		return 0
+	}
+	return iid
+}
+
+func (gl *GitLabContext) Token() string {
+	return gl.token
 }
 
 func (gl *GitLabContext) SetOutput(output OutputMap) {
@@ -75,8 +206,12 @@ func (gl *GitLabContext) SetOutput(output OutputMap) {
 func (gl *GitLabContext) CloseOutput() (err error) {
 	log.Printf("Gitlab flushing output")
 
+	if err = gl.prepareWriteDir(); err != nil {
+		return
+	}
+
 	// Create output file
-	file, err := os.Create(".env")
+	file, err := os.Create(filepath.Join(gl.WriteDir(), ".env"))
 	if err != nil {
 		return
 	}
@@ -87,7 +222,7 @@ func (gl *GitLabContext) CloseOutput() (err error) {
 	var lines []string
 	for k, v := range gl.output {
 		if v.MultiLine() {
-			if err = writeArtifact(gl.jobName, k, v.String()); err != nil {
+			if err = writeArtifact(gl.WriteDir(), gl.jobName, k, v.String()); err != nil {
 				return
 			}
 			continue
@@ -105,20 +240,115 @@ func (gl *GitLabContext) CloseOutput() (err error) {
 	return
 }
 
+// subset of the Code Climate issue format accepted by GitLab
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// writes a Code Quality report so diagnostics are shown inline on the merge request diff,
+// diagnostics that are not tied to a file cannot be displayed and are skipped
+func (gl *GitLabContext) ReportDiagnostics(diags []*Diagnostic) error {
+	issues := []codeQualityIssue{}
+	for _, d := range diags {
+		if d.Filename == "" {
+			continue
+		}
+
+		severity := "major"
+		if d.Severity == "warning" {
+			severity = "minor"
+		}
+
+		description := d.Summary
+		if d.Detail != "" {
+			description = fmt.Sprintf("%s: %s", d.Summary, d.Detail)
+		}
+
+		fingerprint := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", d.Severity, d.Summary, d.Filename, d.Line)))
+
+		issues = append(issues, codeQualityIssue{
+			Description: description,
+			CheckName:   "terraform",
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+			Severity:    severity,
+			Location: codeQualityLocation{
+				Path: d.Filename,
+				Lines: codeQualityLines{
+					Begin: d.Line,
+					End:   d.EndLine,
+				},
+			},
+		})
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := gl.prepareWriteDir(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(gl.WriteDir(), GitLabCodeQualityReport), data, 0644)
+}
+
+// writes the Terraform report read by the merge request widget
+func (gl *GitLabContext) ReportPlan(report *PlanReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	if err := gl.prepareWriteDir(); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gl.WriteDir(), GitLabTerraformReport), data, 0644)
+}
+
 func generateArtifactFileName(ext string, parts ...string) string {
 	return fmt.Sprintf("%s.%s", strings.Join(parts, "_"), ext)
 }
 
 func newGitLabContext(getenv GetEnv) *GitLabContext {
 	return &GitLabContext{
-		concurrentId:        getenv("CI_CONCURRENT_ID"),
-		concurrentProjectId: getenv("CI_CONCURRENT_PROJECT_ID"),
-		jobName:             getenv("CI_JOB_NAME"),
-		commitSHA:           getenv("CI_COMMIT_SHA"),
-		commitSHAShort:      getenv("CI_COMMIT_SHORT_SHA"),
-		commitAuthor:        getenv("CI_COMMIT_AUTHOR"),
-		commitMessage:       getenv("CI_COMMIT_MESSAGE"),
-		commitRefName:       getenv("CI_COMMIT_REF_NAME"),
-		output:              make(map[string]OutputWriter),
+		concurrentId:                getenv("CI_CONCURRENT_ID"),
+		concurrentProjectId:         getenv("CI_CONCURRENT_PROJECT_ID"),
+		jobName:                     getenv("CI_JOB_NAME"),
+		jobURL:                      getenv("CI_JOB_URL"),
+		commitSHA:                   getenv("CI_COMMIT_SHA"),
+		commitSHAShort:              getenv("CI_COMMIT_SHORT_SHA"),
+		commitAuthor:                getenv("CI_COMMIT_AUTHOR"),
+		commitMessage:               getenv("CI_COMMIT_MESSAGE"),
+		commitRefName:               getenv("CI_COMMIT_REF_NAME"),
+		artifactDir:                 getenv(envArtifactDir),
+		apiURL:                      getenv("CI_API_V4_URL"),
+		projectID:                   getenv("CI_PROJECT_ID"),
+		projectURL:                  getenv("CI_PROJECT_URL"),
+		mergeRequestIID:             getenv("CI_MERGE_REQUEST_IID"),
+		mergeRequestTitle:           getenv("CI_MERGE_REQUEST_TITLE"),
+		mergeRequestSourceBranch:    getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"),
+		mergeRequestTargetBranch:    getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME"),
+		mergeRequestSourceBranchSHA: getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_SHA"),
+		mergeRequestEventType:       getenv("CI_MERGE_REQUEST_EVENT_TYPE"),
+		mergeRequestLabels:          getenv("CI_MERGE_REQUEST_LABELS"),
+		mergeRequestProjectURL:      getenv("CI_MERGE_REQUEST_PROJECT_URL"),
+		serverURL:                   getenv("CI_SERVER_URL"),
+		token:                       getenv("GITLAB_TOKEN"),
+		output:                      make(map[string]OutputWriter),
 	}
 }
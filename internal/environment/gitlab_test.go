@@ -4,7 +4,9 @@
 package environment
 
 import (
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -12,6 +14,10 @@ import (
 func TestCloseOutput(t *testing.T) {
 	// Dummy env generation
 	getenv := func(k string) string {
+		// write artifacts to the current directory
+		if k == envArtifactDir {
+			return ""
+		}
 		return "something"
 	}
 
@@ -87,3 +93,163 @@ func TestCloseOutput(t *testing.T) {
 	os.Remove(".env")
 
 }
+
+func TestGitLabReportDiagnostics(t *testing.T) {
+	gitlab := newGitLabContext(func(k string) string { return "" })
+
+	var _ DiagnosticReporter = gitlab
+
+	err := gitlab.ReportDiagnostics([]*Diagnostic{
+		{Severity: "error", Summary: "Unsupported argument", Detail: "not expected here", Filename: "infra/main.tf", Line: 3, EndLine: 4},
+		{Severity: "warning", Summary: "Deprecated", Filename: "infra/vars.tf", Line: 10},
+		{Severity: "error", Summary: "No file"},
+	})
+	if err != nil {
+		t.Fatalf("error reporting diagnostics: %s", err)
+	}
+	t.Cleanup(func() {
+		os.Remove(GitLabCodeQualityReport)
+	})
+
+	contents, err := os.ReadFile(GitLabCodeQualityReport)
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(contents, &issues); err != nil {
+		t.Fatalf("report is not valid json: %s", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, but received: %d", len(issues))
+	}
+	if issues[0].Severity != "major" || issues[0].Location.Path != "infra/main.tf" || issues[0].Location.Lines.Begin != 3 || issues[0].Description != "Unsupported argument: not expected here" {
+		t.Errorf("unexpected issue: %+v", issues[0])
+	}
+	if issues[1].Severity != "minor" || issues[1].Description != "Deprecated" {
+		t.Errorf("unexpected issue: %+v", issues[1])
+	}
+	if issues[0].Fingerprint == "" || issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Errorf("expected unique fingerprints, but received: %q, %q", issues[0].Fingerprint, issues[1].Fingerprint)
+	}
+}
+
+func TestGitLabReportPlan(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	gitlab := newGitLabContext(func(k string) string {
+		if k == envArtifactDir {
+			return dir
+		}
+		return ""
+	})
+
+	var _ PlanReporter = gitlab
+
+	if gitlab.WriteDir() != dir {
+		t.Errorf("expected write dir %q, but received: %q", dir, gitlab.WriteDir())
+	}
+
+	if err := gitlab.ReportPlan(&PlanReport{Create: 2, Update: 1, Delete: 3}); err != nil {
+		t.Fatalf("error reporting plan: %s", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, GitLabTerraformReport))
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	expected := `{"create":2,"update":1,"delete":3}`
+	if string(contents) != expected {
+		t.Errorf("expected %q, but received: %q", expected, string(contents))
+	}
+}
+
+func TestGitLabPullRequest(t *testing.T) {
+	testCases := []struct {
+		name          string
+		env           map[string]string
+		expected      *PullRequest
+		expectedRef   string
+		expectedNilPR bool
+	}{
+		{
+			name:          "branch pipeline",
+			env:           map[string]string{"CI_COMMIT_REF_NAME": "main", "CI_COMMIT_SHA": "abc123"},
+			expectedRef:   "main",
+			expectedNilPR: true,
+		},
+		{
+			name: "merge request pipeline",
+			env: map[string]string{
+				"CI_COMMIT_REF_NAME":                  "feature",
+				"CI_COMMIT_SHA":                       "abc123",
+				"CI_PROJECT_URL":                      "https://gitlab.com/group/infra",
+				"CI_MERGE_REQUEST_IID":                "12",
+				"CI_MERGE_REQUEST_TITLE":              "Add networking module",
+				"CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature",
+				"CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main",
+				"CI_MERGE_REQUEST_EVENT_TYPE":         "detached",
+				"CI_MERGE_REQUEST_LABELS":             "terraform,network",
+			},
+			expected: &PullRequest{
+				Number:  12,
+				Title:   "Add networking module",
+				URL:     "https://gitlab.com/group/infra/-/merge_requests/12",
+				HeadRef: "feature",
+				BaseRef: "main",
+				HeadSHA: "abc123",
+				Labels:  []string{"terraform", "network"},
+			},
+			expectedRef: "feature",
+		},
+		{
+			name: "merged results pipeline",
+			env: map[string]string{
+				"CI_COMMIT_REF_NAME":                  "refs/merge-requests/12/merge",
+				"CI_COMMIT_SHA":                       "merge456",
+				"CI_MERGE_REQUEST_IID":                "12",
+				"CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature",
+				"CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main",
+				"CI_MERGE_REQUEST_SOURCE_BRANCH_SHA":  "abc123",
+				"CI_MERGE_REQUEST_EVENT_TYPE":         "merged_result",
+			},
+			expected: &PullRequest{
+				Number:         12,
+				HeadRef:        "feature",
+				BaseRef:        "main",
+				HeadSHA:        "abc123",
+				MergeCommitSHA: "merge456",
+			},
+			expectedRef: "feature",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gitlab := newGitLabContext(func(key string) string {
+				return tc.env[key]
+			})
+
+			if ref := gitlab.Ref(); ref != tc.expectedRef {
+				t.Errorf("expected ref %s, but received: %s", tc.expectedRef, ref)
+			}
+
+			pr := gitlab.PullRequest()
+			if tc.expectedNilPR {
+				if pr != nil {
+					t.Errorf("expected no merge request, but received: %+v", pr)
+				}
+				return
+			}
+			if pr == nil {
+				t.Fatal("expected merge request, but received nil")
+			}
+			actual, _ := json.Marshal(pr)
+			expected, _ := json.Marshal(tc.expected)
+			if string(actual) != string(expected) {
+				t.Errorf("expected %s, but received: %s", expected, actual)
+			}
+		})
+	}
+}
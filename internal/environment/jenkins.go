@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// properties file written to the workspace, load it with `readProperties` or the EnvInject plugin
+const JenkinsOutputFile = "tfci.properties"
+
+// Sourced from: https://www.jenkins.io/doc/book/pipeline/jenkinsfile/#using-environment-variables
+type JenkinsContext struct {
+	// Full URL of Jenkins, detected to determine the platform
+	jenkinsURL string
+	// String of "jenkins-${JOB_NAME}-${BUILD_NUMBER}", convenient to put into a resource file or jar file for easier identification
+	buildTag string
+	// The current build number, such as "153"
+	buildNumber string
+	// Full URL of this build, like https://server:port/jenkins/job/foo/15/
+	buildURL string
+	// The commit hash being checked out, set by the git plugin
+	gitCommit string
+	// The author of the commit, set by the git plugin when configured
+	gitAuthorName string
+	// The author of a change request, only set for multibranch pull request builds
+	changeAuthor string
+	// The remote branch name, set by the git plugin
+	gitBranch string
+	// The remote URL of the repository, set by the git plugin
+	gitURL string
+	// Name of the branch being built in multibranch projects, eg. main or PR-12
+	branchName string
+	// The change ID, such as a pull request number, only set for multibranch pull request builds
+	changeID string
+	// The pull request title
+	changeTitle string
+	// The change URL
+	changeURL string
+	// The name of the actual head on the source control system, eg. the source branch of a pull request
+	changeBranch string
+	// The target or base branch to which the change could be merged
+	changeTarget string
+	// The absolute path of the directory assigned to the build as a workspace
+	workspace string
+	// The map containing output data
+	output OutputMap
+}
+
+func (j *JenkinsContext) ID() string {
+	return j.buildTag
+}
+
+func (j *JenkinsContext) SHA() string {
+	return j.gitCommit
+}
+
+func (j *JenkinsContext) SHAShort() string {
+	if len(j.gitCommit) > 7 {
+		return j.gitCommit[:7]
+	}
+	return j.gitCommit
+}
+
+func (j *JenkinsContext) Author() string {
+	if j.changeAuthor != "" {
+		return j.changeAuthor
+	}
+	return j.gitAuthorName
+}
+
+// the pull request source branch, otherwise the branch being built
+func (j *JenkinsContext) Ref() string {
+	if j.changeBranch != "" {
+		return j.changeBranch
+	}
+	if j.branchName != "" {
+		return j.branchName
+	}
+	// git plugin includes the remote name, eg. origin/main or refs/remotes/upstream/main
+	if ref, ok := strings.CutPrefix(j.gitBranch, "refs/remotes/"); ok {
+		if _, branch, ok := strings.Cut(ref, "/"); ok {
+			return branch
+		}
+	}
+	return strings.TrimPrefix(j.gitBranch, "origin/")
+}
+
+func (j *JenkinsContext) PullRequest() *PullRequest {
+	number, err := strconv.Atoi(j.changeID)
+	if err != nil {
+		return nil
+	}
+	return &PullRequest{
+		Number:  number,
+		Title:   j.changeTitle,
+		URL:     j.changeURL,
+		HeadRef: j.changeBranch,
+		BaseRef: j.changeTarget,
+	}
+}
+
+func (j *JenkinsContext) ServerURL() string {
+	return serverWebURL(j.RepositoryURL())
+}
+
+// repository URL is set as a git remote and converted to a web URL
+func (j *JenkinsContext) RepositoryURL() string {
+	return repositoryWebURL(j.gitURL)
+}
+
+func (j *JenkinsContext) JobURL() string {
+	return j.buildURL
+}
+
+func (j *JenkinsContext) WriteDir() string {
+	return j.workspace
+}
+
+func (j *JenkinsContext) SetOutput(output OutputMap) {
+	j.output = output
+}
+
+func (j *JenkinsContext) CloseOutput() (err error) {
+	log.Printf("[DEBUG] Jenkins flushing output")
+
+	file, err := os.Create(filepath.Join(j.WriteDir(), JenkinsOutputFile))
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	var lines []string
+	for k, v := range j.output {
+		// keep properties file readable, store complex values as separate workspace files
+		if v.MultiLine() {
+			if err = writeArtifact(j.WriteDir(), "tfci", k, v.String()); err != nil {
+				return
+			}
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s=%s", k, escapeProperty(v.String())))
+	}
+
+	if _, err = file.WriteString(strings.Join(lines, EOF)); err != nil {
+		return
+	}
+
+	// reset output
+	j.output = make(map[string]OutputWriter)
+
+	return
+}
+
+// escapes value according to java.util.Properties load format
+func escapeProperty(v string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return replacer.Replace(v)
+}
+
+func newJenkinsContext(getenv GetEnv) *JenkinsContext {
+	return &JenkinsContext{
+		jenkinsURL:    getenv("JENKINS_URL"),
+		buildTag:      getenv("BUILD_TAG"),
+		buildNumber:   getenv("BUILD_NUMBER"),
+		buildURL:      getenv("BUILD_URL"),
+		gitCommit:     getenv("GIT_COMMIT"),
+		gitAuthorName: getenv("GIT_AUTHOR_NAME"),
+		changeAuthor:  getenv("CHANGE_AUTHOR"),
+		gitBranch:     getenv("GIT_BRANCH"),
+		gitURL:        getenv("GIT_URL"),
+		branchName:    getenv("BRANCH_NAME"),
+		changeID:      getenv("CHANGE_ID"),
+		changeTitle:   getenv("CHANGE_TITLE"),
+		changeURL:     getenv("CHANGE_URL"),
+		changeBranch:  getenv("CHANGE_BRANCH"),
+		changeTarget:  getenv("CHANGE_TARGET"),
+		workspace:     getenv("WORKSPACE"),
+		output:        make(map[string]OutputWriter),
+	}
+}
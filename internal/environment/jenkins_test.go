@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getJenkinsEnvMock(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"JENKINS_URL":     "https://jenkins.example.com/",
+		"BUILD_TAG":       "jenkins-infra-plan-12",
+		"GIT_COMMIT":      randomSha(t),
+		"GIT_AUTHOR_NAME": "Jane Doe",
+		"WORKSPACE":       t.TempDir(),
+	}
+}
+
+func Test_JenkinsContext(t *testing.T) {
+	env := getJenkinsEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	jenkins := newJenkinsContext(getenv)
+
+	if actualID := jenkins.ID(); actualID != env["BUILD_TAG"] {
+		t.Errorf("expected %s, but received: %s", env["BUILD_TAG"], actualID)
+	}
+
+	if actualSHA := jenkins.SHA(); actualSHA != env["GIT_COMMIT"] {
+		t.Errorf("expected %s, but received: %s", env["GIT_COMMIT"], actualSHA)
+	}
+
+	if actualAuthor := jenkins.Author(); actualAuthor != env["GIT_AUTHOR_NAME"] {
+		t.Errorf("expected %s, but received: %s", env["GIT_AUTHOR_NAME"], actualAuthor)
+	}
+
+	if actualDir := jenkins.WriteDir(); actualDir != env["WORKSPACE"] {
+		t.Errorf("expected %s, but received: %s", env["WORKSPACE"], actualDir)
+	}
+
+	// change request author takes precedence for multibranch pull request builds
+	env["CHANGE_AUTHOR"] = "octocat"
+	jenkins = newJenkinsContext(getenv)
+	if actualAuthor := jenkins.Author(); actualAuthor != "octocat" {
+		t.Errorf("expected %s, but received: %s", "octocat", actualAuthor)
+	}
+
+	if pr := jenkins.PullRequest(); pr != nil {
+		t.Errorf("expected no pull request, but received: %+v", pr)
+	}
+
+	// multibranch pull request builds expose the change request
+	env["CHANGE_ID"] = "9"
+	env["CHANGE_BRANCH"] = "feature"
+	env["CHANGE_TARGET"] = "main"
+	env["GIT_URL"] = "git@github.com:octocat/infra.git"
+	jenkins = newJenkinsContext(getenv)
+	pr := jenkins.PullRequest()
+	if pr == nil || pr.Number != 9 || pr.HeadRef != "feature" || pr.BaseRef != "main" {
+		t.Errorf("unexpected pull request: %+v", pr)
+	}
+	if ref := jenkins.Ref(); ref != "feature" {
+		t.Errorf("expected %s, but received: %s", "feature", ref)
+	}
+	if url := jenkins.RepositoryURL(); url != "https://github.com/octocat/infra" {
+		t.Errorf("expected %s, but received: %s", "https://github.com/octocat/infra", url)
+	}
+}
+
+func Test_JenkinsRef(t *testing.T) {
+	testCases := []struct {
+		gitBranch string
+		expected  string
+	}{
+		{gitBranch: "main", expected: "main"},
+		{gitBranch: "origin/main", expected: "main"},
+		{gitBranch: "origin/feature/foo", expected: "feature/foo"},
+		{gitBranch: "feature/foo", expected: "feature/foo"},
+		{gitBranch: "refs/remotes/origin/main", expected: "main"},
+		{gitBranch: "refs/remotes/upstream/feature/foo", expected: "feature/foo"},
+	}
+
+	for _, tc := range testCases {
+		env := getJenkinsEnvMock(t)
+		env["GIT_BRANCH"] = tc.gitBranch
+		jenkins := newJenkinsContext(func(key string) string {
+			return env[key]
+		})
+		if ref := jenkins.Ref(); ref != tc.expected {
+			t.Errorf("expected %s for GIT_BRANCH %s, but received: %s", tc.expected, tc.gitBranch, ref)
+		}
+	}
+}
+
+func Test_JenkinsOutput(t *testing.T) {
+	env := getJenkinsEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	jenkins := newJenkinsContext(getenv)
+
+	jenkins.SetOutput(OutputMap{
+		"run_id":      &testOutput{val: "run-abc123"},
+		"run_message": &testOutput{val: `C:\path`},
+		"payload":     &testOutput{val: `{"pk": "pv"}`, multiLine: true},
+	})
+
+	if err := jenkins.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(env["WORKSPACE"], JenkinsOutputFile))
+	if err != nil {
+		t.Fatalf("file read error: %v", err)
+	}
+
+	props := make(map[string]string)
+	for _, l := range strings.Split(string(contents), "\n") {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 {
+			t.Fatalf("line %s was not in correct properties format", l)
+		}
+		props[kv[0]] = kv[1]
+	}
+
+	if props["run_id"] != "run-abc123" {
+		t.Errorf("expected %s, but received: %s", "run-abc123", props["run_id"])
+	}
+
+	if props["run_message"] != `C:\\path` {
+		t.Errorf("expected %s, but received: %s", `C:\\path`, props["run_message"])
+	}
+
+	if _, exists := props["payload"]; exists {
+		t.Errorf("multiline value should not be stored in properties file")
+	}
+
+	payload, err := os.ReadFile(filepath.Join(env["WORKSPACE"], generateArtifactFileName("json", "tfci", "payload")))
+	if err != nil {
+		t.Fatalf("artifact read error: %v", err)
+	}
+	if string(payload) != `{"pk": "pv"}` {
+		t.Errorf("unexpected artifact contents: %q", string(payload))
+	}
+}
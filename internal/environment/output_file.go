@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// formats supported when writing outputs to a file with `-output-file`
+var fileOutputFormats = []OutputFormat{
+	OutputFormatJSON,
+	OutputFormatDotenv,
+	OutputFormatShell,
+	OutputFormatTfvars,
+}
+
+// ParseFileOutputFormat validates the format used to write outputs to a file, defaults to json
+func ParseFileOutputFormat(v string) (OutputFormat, error) {
+	if v == "" {
+		return OutputFormatJSON, nil
+	}
+	for _, f := range fileOutputFormats {
+		if OutputFormat(v) == f {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported output format: %q, expected one of: %v", v, fileOutputFormats)
+}
+
+// WriteOutputFile writes all outputs to path, independent of the detected CI platform.
+// Multiline values cannot be represented in dotenv files and are written as separate
+// `tfci_<output>.json` files next to it.
+func WriteOutputFile(path string, format OutputFormat, output OutputMap) error {
+	var content string
+	var err error
+
+	switch format {
+	case OutputFormatDotenv:
+		for k, v := range output {
+			if !v.MultiLine() {
+				continue
+			}
+			if err = writeArtifact(filepath.Dir(path), "tfci", k, v.String()); err != nil {
+				return err
+			}
+		}
+		content = formatDotenv(output) + EOF
+	case OutputFormatShell:
+		content = formatShell(output) + EOF
+	case OutputFormatTfvars:
+		if content, err = formatTfvars(output); err != nil {
+			return err
+		}
+		content += EOF
+	case OutputFormatJSON:
+		if content, err = formatJSON(output); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", format)
+	}
+
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFileOutputFormat(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected OutputFormat
+		err      bool
+	}{
+		{value: "", expected: OutputFormatJSON},
+		{value: "json", expected: OutputFormatJSON},
+		{value: "dotenv", expected: OutputFormatDotenv},
+		{value: "shell", expected: OutputFormatShell},
+		{value: "tfvars", expected: OutputFormatTfvars},
+		{value: "template", err: true},
+		{value: "yaml", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			format, err := ParseFileOutputFormat(tc.value)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error for format %q", tc.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if format != tc.expected {
+				t.Errorf("expected format %q, but received: %q", tc.expected, format)
+			}
+		})
+	}
+}
+
+func TestWriteOutputFile(t *testing.T) {
+	output := OutputMap{
+		"run_id":  &testOutput{val: "run-abc123"},
+		"message": &testOutput{val: "it's ${var.x} \"quoted\""},
+		"payload": &testOutput{val: "{\n  \"pk\": \"pv\"\n}", multiLine: true},
+	}
+
+	testCases := []struct {
+		name      string
+		format    OutputFormat
+		expected  string
+		artifacts []string
+	}{
+		{
+			name:      "dotenv",
+			format:    OutputFormatDotenv,
+			expected:  "message=it's ${var.x} \"quoted\"\nrun_id=run-abc123\n",
+			artifacts: []string{"tfci_payload.json"},
+		},
+		{
+			name:     "shell",
+			format:   OutputFormatShell,
+			expected: "export message='it'\\''s ${var.x} \"quoted\"'\nexport payload='{\n  \"pk\": \"pv\"\n}'\nexport run_id='run-abc123'\n",
+		},
+		{
+			name:     "tfvars",
+			format:   OutputFormatTfvars,
+			expected: "message = \"it's $${var.x} \\\"quoted\\\"\"\npayload = \"{\\n  \\\"pk\\\": \\\"pv\\\"\\n}\"\nrun_id = \"run-abc123\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "outputs")
+
+			if err := WriteOutputFile(path, tc.format, output); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("file read error: %v", err)
+			}
+			if string(contents) != tc.expected {
+				t.Errorf("expected %q, but received: %q", tc.expected, string(contents))
+			}
+
+			for _, artifact := range tc.artifacts {
+				if _, err := os.Stat(filepath.Join(dir, artifact)); err != nil {
+					t.Errorf("expected artifact %q to be written: %s", artifact, err)
+				}
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "outputs.json")
+		if err := WriteOutputFile(path, OutputFormatJSON, output); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("file read error: %v", err)
+		}
+		data := map[string]string{}
+		if err := json.Unmarshal(contents, &data); err != nil {
+			t.Fatalf("output file is not valid json: %s", err)
+		}
+		for k, v := range output {
+			if data[k] != v.String() {
+				t.Errorf("expected %q for %q, but received: %q", v.String(), k, data[k])
+			}
+		}
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type OutputFormat string
+
+const (
+	OutputFormatDotenv   OutputFormat = "dotenv"
+	OutputFormatJSON     OutputFormat = "json"
+	OutputFormatShell    OutputFormat = "shell"
+	OutputFormatTemplate OutputFormat = "template"
+	OutputFormatTfvars   OutputFormat = "tfvars"
+)
+
+// returns output keys in a stable order, so written files are reproducible
+func sortedKeys(output OutputMap) []string {
+	keys := make([]string, 0, len(output))
+	for k := range output {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formats single line values as `key=value`, multiline values are skipped
+// as dotenv does not support them and are expected to be written as artifacts
+func formatDotenv(output OutputMap) string {
+	var lines []string
+	for _, k := range sortedKeys(output) {
+		v := output[k]
+		if v.MultiLine() {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s=%s", k, v.String()))
+	}
+	return strings.Join(lines, EOF)
+}
+
+// formats all values as `export key='value'` lines that can be sourced by a posix shell
+func formatShell(output OutputMap) string {
+	var lines []string
+	for _, k := range sortedKeys(output) {
+		lines = append(lines, shellExport(k, output[k].String()))
+	}
+	return strings.Join(lines, EOF)
+}
+
+// formats all values as a flat json object of strings
+func formatJSON(output OutputMap) (string, error) {
+	data := make(map[string]string, len(output))
+	for k, v := range output {
+		data[k] = v.String()
+	}
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// formats all values as string variable assignments that can be loaded with `-var-file`
+func formatTfvars(output OutputMap) (string, error) {
+	var lines []string
+	for _, k := range sortedKeys(output) {
+		v, err := hclQuote(output[k].String())
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, fmt.Sprintf("%s = %s", k, v))
+	}
+	return strings.Join(lines, EOF), nil
+}
+
+// json string escapes are valid HCL escapes, template sequences must be escaped
+// so values are read literally
+func hclQuote(v string) (string, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	quoted := strings.TrimSuffix(b.String(), "\n")
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{"), nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sourced from: https://www.jetbrains.com/help/teamcity/predefined-build-parameters.html
+type TeamCityContext struct {
+	// The version of TeamCity server, detected to determine the platform
+	version string
+	// The build number assigned to the build by TeamCity
+	buildNumber string
+	// The name of the build configuration the current build belongs to
+	buildConfName string
+	// The latest VCS revision included in the build
+	vcsNumber string
+	// Not exported by default, map `env.TEAMCITY_BUILD_TRIGGEREDBY` to `%teamcity.build.triggeredBy.username%`
+	triggeredBy string
+	// Build temporary directory, cleaned after each build
+	tempDir string
+	// where service messages are written, the agent reads them from stdout
+	stdout io.Writer
+	// The map containing output data
+	output OutputMap
+}
+
+func (tc *TeamCityContext) ID() string {
+	return fmt.Sprintf("tc-%s", tc.buildNumber)
+}
+
+func (tc *TeamCityContext) SHA() string {
+	return tc.vcsNumber
+}
+
+func (tc *TeamCityContext) SHAShort() string {
+	if len(tc.vcsNumber) > 7 {
+		return tc.vcsNumber[:7]
+	}
+	return tc.vcsNumber
+}
+
+func (tc *TeamCityContext) Author() string {
+	return tc.triggeredBy
+}
+
+// branch and pull request details are only available as configuration parameters, not environment variables
+func (tc *TeamCityContext) Ref() string {
+	return ""
+}
+
+func (tc *TeamCityContext) PullRequest() *PullRequest {
+	return nil
+}
+
+func (tc *TeamCityContext) ServerURL() string {
+	return ""
+}
+
+func (tc *TeamCityContext) RepositoryURL() string {
+	return ""
+}
+
+func (tc *TeamCityContext) JobURL() string {
+	return ""
+}
+
+func (tc *TeamCityContext) WriteDir() string {
+	return tc.tempDir
+}
+
+func (tc *TeamCityContext) SetOutput(output OutputMap) {
+	tc.output = output
+}
+
+func (tc *TeamCityContext) CloseOutput() error {
+	log.Printf("[DEBUG] TeamCity flushing output")
+
+	for k, v := range tc.output {
+		value := v.String()
+		// store multiline values as a published artifact, the parameter references the file path
+		if v.MultiLine() {
+			path := filepath.Join(tc.WriteDir(), generateArtifactFileName("json", "tfci", k))
+			if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(tc.stdout, "##teamcity[publishArtifacts '%s']"+EOF, escapeServiceMessage(path)); err != nil {
+				return err
+			}
+			value = path
+		}
+
+		if _, err := fmt.Fprintf(tc.stdout, "##teamcity[setParameter name='env.%s' value='%s']"+EOF, escapeServiceMessage(k), escapeServiceMessage(value)); err != nil {
+			return err
+		}
+	}
+
+	// reset output
+	tc.output = make(map[string]OutputWriter)
+
+	return nil
+}
+
+// sets where service messages are written, defaults to stdout
+func (tc *TeamCityContext) SetCommandWriter(w io.Writer) {
+	tc.stdout = w
+}
+
+// https://www.jetbrains.com/help/teamcity/service-messages.html#Escaped+Values
+func escapeServiceMessage(v string) string {
+	replacer := strings.NewReplacer(
+		"|", "||",
+		"'", "|'",
+		"\n", "|n",
+		"\r", "|r",
+		"[", "|[",
+		"]", "|]",
+	)
+	return replacer.Replace(v)
+}
+
+func newTeamCityContext(getenv GetEnv) *TeamCityContext {
+	tempDir := getenv("TMPDIR")
+	if tempDir == "" {
+		tempDir = getenv("TEMP")
+	}
+
+	return &TeamCityContext{
+		version:       getenv("TEAMCITY_VERSION"),
+		buildNumber:   getenv("BUILD_NUMBER"),
+		buildConfName: getenv("TEAMCITY_BUILDCONF_NAME"),
+		vcsNumber:     getenv("BUILD_VCS_NUMBER"),
+		triggeredBy:   getenv("TEAMCITY_BUILD_TRIGGEREDBY"),
+		tempDir:       tempDir,
+		stdout:        os.Stdout,
+		output:        make(map[string]OutputWriter),
+	}
+}
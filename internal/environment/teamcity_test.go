@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package environment
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getTeamCityEnvMock(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"TEAMCITY_VERSION":           "2024.03 (build 156364)",
+		"BUILD_NUMBER":               "99",
+		"BUILD_VCS_NUMBER":           randomSha(t),
+		"TEAMCITY_BUILD_TRIGGEREDBY": "jane",
+		"TMPDIR":                     t.TempDir(),
+	}
+}
+
+func Test_TeamCityContext(t *testing.T) {
+	env := getTeamCityEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	teamcity := newTeamCityContext(getenv)
+
+	if actualID := teamcity.ID(); actualID != "tc-99" {
+		t.Errorf("expected %s, but received: %s", "tc-99", actualID)
+	}
+
+	if actualSHA := teamcity.SHA(); actualSHA != env["BUILD_VCS_NUMBER"] {
+		t.Errorf("expected %s, but received: %s", env["BUILD_VCS_NUMBER"], actualSHA)
+	}
+
+	if actualAuthor := teamcity.Author(); actualAuthor != env["TEAMCITY_BUILD_TRIGGEREDBY"] {
+		t.Errorf("expected %s, but received: %s", env["TEAMCITY_BUILD_TRIGGEREDBY"], actualAuthor)
+	}
+
+	if actualDir := teamcity.WriteDir(); actualDir != env["TMPDIR"] {
+		t.Errorf("expected %s, but received: %s", env["TMPDIR"], actualDir)
+	}
+}
+
+func Test_TeamCityOutput(t *testing.T) {
+	env := getTeamCityEnvMock(t)
+	getenv := func(key string) string {
+		return env[key]
+	}
+	teamcity := newTeamCityContext(getenv)
+	stdout := new(bytes.Buffer)
+	teamcity.SetCommandWriter(stdout)
+
+	teamcity.SetOutput(OutputMap{
+		"run_message": &testOutput{val: "it's [done]"},
+		"payload":     &testOutput{val: "{\n\"pk\": \"pv\"\n}", multiLine: true},
+	})
+
+	if err := teamcity.CloseOutput(); err != nil {
+		t.Fatalf("close output error: %v", err)
+	}
+
+	expected := "##teamcity[setParameter name='env.run_message' value='it|'s |[done|]']"
+	if !strings.Contains(stdout.String(), expected) {
+		t.Errorf("expected %q in output, but received: %q", expected, stdout.String())
+	}
+
+	path := filepath.Join(env["TMPDIR"], generateArtifactFileName("json", "tfci", "payload"))
+	if !strings.Contains(stdout.String(), "##teamcity[publishArtifacts '"+path+"']") {
+		t.Errorf("expected payload artifact to be published, received: %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "##teamcity[setParameter name='env.payload' value='"+path+"']") {
+		t.Errorf("expected payload parameter to reference %s, received: %q", path, stdout.String())
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("artifact read error: %v", err)
+	}
+	if string(contents) != "{\n\"pk\": \"pv\"\n}" {
+		t.Errorf("unexpected artifact contents: %q", string(contents))
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+)
+
+// check run statuses
+const (
+	CheckStatusQueued     = "queued"
+	CheckStatusInProgress = "in_progress"
+	CheckStatusCompleted  = "completed"
+)
+
+// check run conclusions, required when status is completed
+const (
+	CheckConclusionSuccess   = "success"
+	CheckConclusionFailure   = "failure"
+	CheckConclusionNeutral   = "neutral"
+	CheckConclusionCancelled = "cancelled"
+	CheckConclusionTimedOut  = "timed_out"
+)
+
+// maximum length of check run output fields
+const maxCheckOutputLength = 65535
+
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Text    string `json:"text,omitempty"`
+}
+
+// CheckRun is a commit check, created checks are shown on the commit and its pull requests
+// https://docs.github.com/en/rest/checks/runs
+type CheckRun struct {
+	ID         int64           `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	HeadSHA    string          `json:"head_sha,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Conclusion string          `json:"conclusion,omitempty"`
+	DetailsURL string          `json:"details_url,omitempty"`
+	ExternalID string          `json:"external_id,omitempty"`
+	HTMLURL    string          `json:"html_url,omitempty"`
+	Output     *CheckRunOutput `json:"output,omitempty"`
+}
+
+func truncateOutput(output *CheckRunOutput) {
+	if output == nil {
+		return
+	}
+	output.Summary = truncateString(output.Summary, maxCheckOutputLength)
+	output.Text = truncateString(output.Text, maxCheckOutputLength)
+}
+
+// cuts s to at most max bytes, without splitting a multi-byte character
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// CreateCheckRun creates a check for check.HeadSHA, requires `checks: write` permission
+func (c *Client) CreateCheckRun(ctx context.Context, check *CheckRun) (*CheckRun, error) {
+	truncateOutput(check.Output)
+	result := &CheckRun{}
+	if err := c.do(ctx, http.MethodPost, "check-runs", check, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateCheckRun updates the status, conclusion or output of an existing check
+func (c *Client) UpdateCheckRun(ctx context.Context, id int64, check *CheckRun) (*CheckRun, error) {
+	truncateOutput(check.Output)
+	result := &CheckRun{}
+	if err := c.do(ctx, http.MethodPatch, fmt.Sprintf("check-runs/%d", id), check, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
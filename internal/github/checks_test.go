@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateOutput(t *testing.T) {
+	// the limit falls inside the 3 byte character
+	summary := strings.Repeat("a", maxCheckOutputLength-1) + "€"
+	text := strings.Repeat("ü", maxCheckOutputLength)
+	output := &CheckRunOutput{Title: "plan", Summary: summary, Text: text}
+
+	truncateOutput(output)
+
+	if output.Summary != strings.Repeat("a", maxCheckOutputLength-1) {
+		t.Errorf("expected summary to end before the multi-byte character, but received %d bytes", len(output.Summary))
+	}
+	if len(output.Text) > maxCheckOutputLength || !utf8.ValidString(output.Text) {
+		t.Errorf("expected valid text of at most %d bytes, but received %d bytes", maxCheckOutputLength, len(output.Text))
+	}
+
+	short := &CheckRunOutput{Summary: "Plan: 1 to add ✓"}
+	truncateOutput(short)
+	if short.Summary != "Plan: 1 to add ✓" {
+		t.Errorf("expected short summary to be unchanged, but received: %q", short.Summary)
+	}
+}
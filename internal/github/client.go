@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/tfci/version"
+)
+
+const (
+	// used when GITHUB_API_URL is not set, GitHub Enterprise Server exposes the api at https://<host>/api/v3
+	DefaultAPIURL  = "https://api.github.com"
+	defaultTimeout = 30 * time.Second
+	apiVersion     = "2022-11-28"
+	// identity of the GITHUB_TOKEN of workflow runs, which can not read the authenticated user
+	ActionsBotLogin = "github-actions[bot]"
+)
+
+// APIError is returned for any non 2xx response from the GitHub REST API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("github api responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Client is a minimal GitHub REST API client, scoped to a single repository
+type Client struct {
+	apiURL     string
+	token      string
+	repository string
+	httpClient *http.Client
+	// resolved by AuthenticatedLogin
+	login string
+}
+
+// NewClient returns a client for repository (owner/name), apiURL defaults to DefaultAPIURL
+func NewClient(apiURL string, token string, repository string) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("github token is not set")
+	}
+	if repository == "" {
+		return nil, fmt.Errorf("github repository is not set")
+	}
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	return &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+		repository: repository,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+// AuthenticatedLogin returns the login of the token owner, installation tokens like the GITHUB_TOKEN
+// are denied access to the authenticated user and resolve to ActionsBotLogin
+func (c *Client) AuthenticatedLogin(ctx context.Context) (string, error) {
+	if c.login != "" {
+		return c.login, nil
+	}
+
+	user := struct {
+		Login string `json:"login"`
+	}{}
+	err := c.request(ctx, http.MethodGet, fmt.Sprintf("%s/user", c.apiURL), nil, &user)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusForbidden {
+		user.Login, err = ActionsBotLogin, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	c.login = user.Login
+	return c.login, nil
+}
+
+// sends request to the repository scoped path, decodes json response into out when provided
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	return c.request(ctx, method, fmt.Sprintf("%s/repos/%s/%s", c.apiURL, c.repository, strings.TrimPrefix(path, "/")), body, out)
+}
+
+func (c *Client) request(ctx context.Context, method string, url string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("User-Agent", fmt.Sprintf("tfci/%s", version.GetVersion()))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Printf("[DEBUG] github api request: %s %s", method, url)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		errBody := struct {
+			Message string `json:"message"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(&errBody); err == nil {
+			apiErr.Message = errBody.Message
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const commentsPerPage = 100
+
+type IssueComment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	User    *User  `json:"user,omitempty"`
+}
+
+type User struct {
+	Login string `json:"login"`
+}
+
+// ListIssueComments returns all comments of an issue or pull request
+func (c *Client) ListIssueComments(ctx context.Context, number int) ([]*IssueComment, error) {
+	comments := []*IssueComment{}
+	for page := 1; ; page++ {
+		var result []*IssueComment
+		path := fmt.Sprintf("issues/%d/comments?per_page=%d&page=%d", number, commentsPerPage, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return nil, err
+		}
+		comments = append(comments, result...)
+		if len(result) < commentsPerPage {
+			return comments, nil
+		}
+	}
+}
+
+func (c *Client) CreateIssueComment(ctx context.Context, number int, body string) (*IssueComment, error) {
+	comment := &IssueComment{}
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("issues/%d/comments", number), map[string]string{"body": body}, comment)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (c *Client) UpdateIssueComment(ctx context.Context, id int64, body string) (*IssueComment, error) {
+	comment := &IssueComment{}
+	err := c.do(ctx, http.MethodPatch, fmt.Sprintf("issues/comments/%d", id), map[string]string{"body": body}, comment)
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// UpsertIssueComment edits the comment containing marker in place, or creates one when it does not exist yet.
+// The marker, usually a hidden html comment, is prepended to body when missing. Only comments written by the
+// token owner are edited, so comments of other users quoting the marker are left untouched.
+func (c *Client) UpsertIssueComment(ctx context.Context, number int, marker string, body string) (*IssueComment, error) {
+	if !strings.Contains(body, marker) {
+		body = marker + "\n" + body
+	}
+
+	login, err := c.AuthenticatedLogin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := c.ListIssueComments(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, comment := range comments {
+		if comment.User != nil && comment.User.Login == login && strings.Contains(comment.Body, marker) {
+			return c.UpdateIssueComment(ctx, comment.ID, body)
+		}
+	}
+
+	return c.CreateIssueComment(ctx, number, body)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// in-memory stand-in for the issue comments api of a single pull request
+type commentServer struct {
+	mu       sync.Mutex
+	comments []*IssueComment
+	nextID   int64
+	// login of the token owner, installation tokens are denied access to the user when empty
+	login string
+}
+
+func (s *commentServer) author() string {
+	if s.login == "" {
+		return ActionsBotLogin
+	}
+	return s.login
+}
+
+func (s *commentServer) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if s.login == "" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"Resource not accessible by integration"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"login": s.login})
+	})
+	mux.HandleFunc("/repos/octocat/hello-world/issues/7/comments", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("page") != "1" {
+				json.NewEncoder(w).Encode([]*IssueComment{})
+				return
+			}
+			json.NewEncoder(w).Encode(s.comments)
+		case http.MethodPost:
+			body := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			s.nextID++
+			comment := &IssueComment{ID: s.nextID, Body: body["body"], HTMLURL: fmt.Sprintf("https://github.com/octocat/hello-world/pull/7#issuecomment-%d", s.nextID), User: &User{Login: s.author()}}
+			s.comments = append(s.comments, comment)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(comment)
+		default:
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+	})
+	mux.HandleFunc("/repos/octocat/hello-world/issues/comments/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.Method != http.MethodPatch {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		var id int64
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/repos/octocat/hello-world/issues/comments/"), "%d", &id)
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		for _, c := range s.comments {
+			if c.ID == id {
+				c.Body = body["body"]
+				json.NewEncoder(w).Encode(c)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Not Found"}`)
+	})
+	return mux
+}
+
+func TestClient_UpsertIssueComment(t *testing.T) {
+	server := &commentServer{
+		comments: []*IssueComment{{ID: 100, Body: "LGTM"}},
+		nextID:   100,
+	}
+	ts := httptest.NewServer(server.handler(t))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, "gh-token", "octocat/hello-world")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := context.Background()
+	marker := "<!-- tfci:run-summary workspace=prod -->"
+
+	created, err := client.UpsertIssueComment(ctx, 7, marker, "first plan")
+	if err != nil {
+		t.Fatalf("unexpected error creating comment: %s", err)
+	}
+	if created.ID != 101 || created.Body != marker+"\nfirst plan" {
+		t.Errorf("unexpected comment: %+v", created)
+	}
+
+	updated, err := client.UpsertIssueComment(ctx, 7, marker, "second plan")
+	if err != nil {
+		t.Fatalf("unexpected error updating comment: %s", err)
+	}
+	if updated.ID != created.ID || updated.Body != marker+"\nsecond plan" {
+		t.Errorf("expected comment %d to be edited in place, received: %+v", created.ID, updated)
+	}
+
+	// a different workspace has its own comment
+	if _, err := client.UpsertIssueComment(ctx, 7, "<!-- tfci:run-summary workspace=prod-eu -->", "other plan"); err != nil {
+		t.Fatalf("unexpected error creating comment: %s", err)
+	}
+
+	if len(server.comments) != 3 {
+		t.Errorf("expected 3 comments, but received: %d", len(server.comments))
+	}
+}
+
+func TestClient_UpsertIssueComment_Author(t *testing.T) {
+	marker := "<!-- tfci:run-summary workspace=prod -->"
+	for name, login := range map[string]string{"github-token": "", "personal-token": "octocat"} {
+		t.Run(name, func(t *testing.T) {
+			server := &commentServer{
+				comments: []*IssueComment{
+					// a reviewer quoting the previous plan comment
+					{ID: 100, Body: "> " + marker + "\n> first plan\nwhy?", User: &User{Login: "hubot"}},
+					{ID: 101, Body: marker + "\nfirst plan", User: &User{Login: "someone-else[bot]"}},
+				},
+				nextID: 101,
+				login:  login,
+			}
+			ts := httptest.NewServer(server.handler(t))
+			defer ts.Close()
+
+			client, err := NewClient(ts.URL, "gh-token", "octocat/hello-world")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			created, err := client.UpsertIssueComment(context.Background(), 7, marker, "second plan")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if created.ID != 102 || created.User.Login != server.author() {
+				t.Errorf("expected a new comment by %s, but received: %+v", server.author(), created)
+			}
+			if server.comments[0].Body != "> "+marker+"\n> first plan\nwhy?" || server.comments[1].Body != marker+"\nfirst plan" {
+				t.Errorf("expected comments of other users to be left untouched")
+			}
+
+			updated, err := client.UpsertIssueComment(context.Background(), 7, marker, "third plan")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if updated.ID != created.ID {
+				t.Errorf("expected comment %d to be edited in place, received: %+v", created.ID, updated)
+			}
+		})
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	ts := httptest.NewServer((&commentServer{}).handler(t))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL+"/", "invalid", "octocat/hello-world")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = client.ListIssueComments(context.Background(), 7)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, but received: %#v", err)
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "Bad credentials" {
+		t.Errorf("unexpected api error: %+v", apiErr)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient("", "", "octocat/hello-world"); err == nil {
+		t.Errorf("expected error when token is not set")
+	}
+	if _, err := NewClient("", "gh-token", ""); err == nil {
+		t.Errorf("expected error when repository is not set")
+	}
+	client, err := NewClient("", "gh-token", "octocat/hello-world")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if client.apiURL != DefaultAPIURL {
+		t.Errorf("expected default api url, but received: %s", client.apiURL)
+	}
+}
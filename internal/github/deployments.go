@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// deployment status states
+const (
+	DeploymentStateQueued     = "queued"
+	DeploymentStateInProgress = "in_progress"
+	DeploymentStateSuccess    = "success"
+	DeploymentStateFailure    = "failure"
+	DeploymentStateError      = "error"
+)
+
+// Deployment is a request to deploy a ref to an environment, shown in the environment history of the repository
+// https://docs.github.com/en/rest/deployments/deployments
+type Deployment struct {
+	ID          int64  `json:"id,omitempty"`
+	Ref         string `json:"ref"`
+	SHA         string `json:"sha,omitempty"`
+	Environment string `json:"environment"`
+	Description string `json:"description,omitempty"`
+	AutoMerge   bool   `json:"auto_merge"`
+	// commit status contexts verified before the deployment is created, empty skips verification
+	RequiredContexts []string `json:"required_contexts"`
+}
+
+type DeploymentStatus struct {
+	ID          int64  `json:"id,omitempty"`
+	State       string `json:"state"`
+	LogURL      string `json:"log_url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateDeployment creates a deployment, requires `deployments: write` permission
+func (c *Client) CreateDeployment(ctx context.Context, deployment *Deployment) (*Deployment, error) {
+	if deployment.RequiredContexts == nil {
+		deployment.RequiredContexts = []string{}
+	}
+	result := &Deployment{}
+	if err := c.do(ctx, http.MethodPost, "deployments", deployment, result); err != nil {
+		return nil, err
+	}
+	if result.ID == 0 {
+		// merge requests respond with 202 Accepted and a message instead of a deployment
+		return nil, fmt.Errorf("deployment for %q was not created", deployment.Ref)
+	}
+	return result, nil
+}
+
+func (c *Client) CreateDeploymentStatus(ctx context.Context, deploymentID int64, status *DeploymentStatus) (*DeploymentStatus, error) {
+	result := &DeploymentStatus{}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("deployments/%d/statuses", deploymentID), status, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/tfci/version"
+)
+
+const (
+	// used when CI_API_V4_URL is not set
+	DefaultAPIURL  = "https://gitlab.com/api/v4"
+	defaultTimeout = 30 * time.Second
+)
+
+// APIError is returned for any non 2xx response from the GitLab REST API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gitlab api responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Client is a minimal GitLab REST API client, scoped to a single project
+type Client struct {
+	apiURL    string
+	projectID string
+	// project or personal access token, sent as PRIVATE-TOKEN
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for projectID, authenticating with token (project or personal access token).
+// CI_JOB_TOKEN is not accepted by the notes API. apiURL defaults to DefaultAPIURL
+func NewClient(apiURL string, projectID string, token string) (*Client, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("gitlab project id is not set")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("gitlab token is not set, set GITLAB_TOKEN to a project or personal access token with api scope")
+	}
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+
+	client := &Client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		projectID:  projectID,
+		token:      token,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+
+	return client, nil
+}
+
+// sends request to the project scoped path, decodes json response into out when provided
+func (c *Client) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s/%s", c.apiURL, url.PathEscape(c.projectID), strings.TrimPrefix(path, "/"))
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("User-Agent", fmt.Sprintf("tfci/%s", version.GetVersion()))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	log.Printf("[DEBUG] gitlab api request: %s %s", method, endpoint)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		// message is either a string or an object of validation errors
+		errBody := struct {
+			Message json.RawMessage `json:"message"`
+			Error   string          `json:"error"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(&errBody); err == nil {
+			var message string
+			if err := json.Unmarshal(errBody.Message, &message); err != nil {
+				message = string(errBody.Message)
+			}
+			if message == "" {
+				message = errBody.Error
+			}
+			apiErr.Message = message
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
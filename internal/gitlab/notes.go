@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const notesPerPage = 100
+
+type Note struct {
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+	System bool   `json:"system"`
+}
+
+// ListMergeRequestNotes returns all notes of a merge request
+func (c *Client) ListMergeRequestNotes(ctx context.Context, iid int) ([]*Note, error) {
+	notes := []*Note{}
+	for page := 1; ; page++ {
+		var result []*Note
+		path := fmt.Sprintf("merge_requests/%d/notes?per_page=%d&page=%d", iid, notesPerPage, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return nil, err
+		}
+		notes = append(notes, result...)
+		if len(result) < notesPerPage {
+			return notes, nil
+		}
+	}
+}
+
+func (c *Client) CreateMergeRequestNote(ctx context.Context, iid int, body string) (*Note, error) {
+	note := &Note{}
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("merge_requests/%d/notes", iid), map[string]string{"body": body}, note)
+	if err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (c *Client) UpdateMergeRequestNote(ctx context.Context, iid int, id int64, body string) (*Note, error) {
+	note := &Note{}
+	err := c.do(ctx, http.MethodPut, fmt.Sprintf("merge_requests/%d/notes/%d", iid, id), map[string]string{"body": body}, note)
+	if err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// UpsertMergeRequestNote edits the note containing marker in place, or creates one when it does not exist yet.
+// The marker, usually a hidden html comment, is prepended to body when missing.
+func (c *Client) UpsertMergeRequestNote(ctx context.Context, iid int, marker string, body string) (*Note, error) {
+	if !strings.Contains(body, marker) {
+		body = marker + "\n" + body
+	}
+
+	notes, err := c.ListMergeRequestNotes(ctx, iid)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		if !note.System && strings.Contains(note.Body, marker) {
+			return c.UpdateMergeRequestNote(ctx, iid, note.ID, body)
+		}
+	}
+
+	return c.CreateMergeRequestNote(ctx, iid, body)
+}
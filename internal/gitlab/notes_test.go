@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// in-memory stand-in for the notes api of a single merge request
+type noteServer struct {
+	mu     sync.Mutex
+	notes  []*Note
+	nextID int64
+	// expected auth header and value
+	header string
+	token  string
+}
+
+func (s *noteServer) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if r.Header.Get(s.header) != s.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"401 Unauthorized"}`)
+			return
+		}
+
+		// project ids and paths are url encoded
+		path := r.URL.EscapedPath()
+		prefix := "/api/v4/projects/group%2Fproject/merge_requests/3/notes"
+		if !strings.HasPrefix(path, prefix) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 Project Not Found"}`)
+			return
+		}
+
+		body := map[string]string{}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("page") != "1" {
+				json.NewEncoder(w).Encode([]*Note{})
+				return
+			}
+			json.NewEncoder(w).Encode(s.notes)
+		case http.MethodPost:
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["body"] == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"message":{"note":["can't be blank"]}}`)
+				return
+			}
+			s.nextID++
+			note := &Note{ID: s.nextID, Body: body["body"]}
+			s.notes = append(s.notes, note)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(note)
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&body)
+			var id int64
+			fmt.Sscanf(strings.TrimPrefix(path, prefix+"/"), "%d", &id)
+			for _, n := range s.notes {
+				if n.ID == id {
+					n.Body = body["body"]
+					json.NewEncoder(w).Encode(n)
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 Not found"}`)
+		default:
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+	})
+}
+
+func TestClient_UpsertMergeRequestNote(t *testing.T) {
+	marker := "<!-- tfci:run-summary workspace=prod -->"
+	server := &noteServer{
+		notes: []*Note{
+			// system notes are never edited, even if they quote the marker
+			{ID: 10, Body: "mentioned " + marker, System: true},
+		},
+		nextID: 10,
+		header: "PRIVATE-TOKEN",
+		token:  "glpat-token",
+	}
+	ts := httptest.NewServer(server.handler(t))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL+"/api/v4", "group/project", "glpat-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	ctx := context.Background()
+
+	created, err := client.UpsertMergeRequestNote(ctx, 3, marker, "first plan")
+	if err != nil {
+		t.Fatalf("unexpected error creating note: %s", err)
+	}
+	if created.ID != 11 || created.Body != marker+"\nfirst plan" {
+		t.Errorf("unexpected note: %+v", created)
+	}
+
+	updated, err := client.UpsertMergeRequestNote(ctx, 3, marker, "second plan")
+	if err != nil {
+		t.Fatalf("unexpected error updating note: %s", err)
+	}
+	if updated.ID != created.ID || updated.Body != marker+"\nsecond plan" {
+		t.Errorf("expected note %d to be edited in place, received: %+v", created.ID, updated)
+	}
+
+	// a different workspace has its own note
+	if _, err := client.UpsertMergeRequestNote(ctx, 3, "<!-- tfci:run-summary workspace=staging -->", "other plan"); err != nil {
+		t.Fatalf("unexpected error creating note: %s", err)
+	}
+
+	if len(server.notes) != 3 {
+		t.Errorf("expected 3 notes, but received: %d", len(server.notes))
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	server := &noteServer{header: "PRIVATE-TOKEN", token: "glpat-token"}
+	ts := httptest.NewServer(server.handler(t))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL+"/api/v4/", "group/project", "glpat-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = client.CreateMergeRequestNote(context.Background(), 3, "")
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, but received: %#v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != `{"note":["can't be blank"]}` {
+		t.Errorf("unexpected api error: %+v", apiErr)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	testCases := []struct {
+		name      string
+		projectID string
+		token     string
+		err       bool
+	}{
+		{name: "access-token", projectID: "123", token: "glpat-token"},
+		{name: "no-token", projectID: "123", err: true},
+		{name: "no-project", token: "glpat-token", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := NewClient("", tc.projectID, tc.token)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if client.token != tc.token {
+				t.Errorf("expected token %q, but received: %q", tc.token, client.token)
+			}
+			if client.apiURL != DefaultAPIURL {
+				t.Errorf("expected default api url, but received: %q", client.apiURL)
+			}
+		})
+	}
+}
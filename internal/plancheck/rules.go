@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package plancheck evaluates resource changes of a plan against local rules,
+// for guardrails in workspaces without Sentinel or OPA policy sets.
+package plancheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+// Rules is a rules file, rules are evaluated in order
+//
+//	{
+//	  "rules": [
+//	    {"name": "keep-buckets", "resource_types": ["aws_s3_bucket"], "actions": ["delete", "replace"], "deny": true},
+//	    {"name": "small-changes", "max_changes": 20},
+//	    {"name": "stable-network", "modules": ["module.network"], "actions": ["replace"], "deny": true},
+//	    {"name": "iam-review", "resource_types": ["aws_iam_*"], "require_labels": ["iam-approved"]}
+//	  ]
+//	}
+type Rules struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule selects resource changes and sets exactly one condition for them. Selectors
+// left empty match all changes, except data source reads which are only matched when
+// listed in actions. Patterns may contain `*` wildcards.
+type Rule struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// change actions: create, update, replace, delete or read, all but read when empty
+	Actions       []string `json:"actions,omitempty"`
+	ResourceTypes []string `json:"resource_types,omitempty"`
+	Addresses     []string `json:"addresses,omitempty"`
+	// module addresses, matching resources of nested modules as well
+	Modules []string `json:"modules,omitempty"`
+
+	// no change may match
+	Deny bool `json:"deny,omitempty"`
+	// at most this many changes may match
+	MaxChanges *int `json:"max_changes,omitempty"`
+	// matching changes require all labels, eg. of the pull request
+	RequireLabels []string `json:"require_labels,omitempty"`
+}
+
+// Violation is a resource change or plan failing a rule
+type Violation struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description,omitempty"`
+	Message     string `json:"message"`
+	// violating resource change, empty for rules over all matching changes
+	Address string `json:"address,omitempty"`
+	Action  string `json:"action,omitempty"`
+}
+
+// LoadRules reads and validates a rules file
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rules file: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// ParseRules decodes and validates rules, unknown fields are rejected so misspelled conditions are not ignored
+func ParseRules(data []byte) (*Rules, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	rules := &Rules{}
+	if err := decoder.Decode(rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+	return rules, nil
+}
+
+// Validate checks each rule has a unique name, known actions and exactly one condition
+func (r *Rules) Validate() error {
+	if len(r.Rules) == 0 {
+		return fmt.Errorf("no rules defined")
+	}
+	names := map[string]bool{}
+	for i, rule := range r.Rules {
+		if rule == nil || rule.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %q: name is not unique", rule.Name)
+		}
+		names[rule.Name] = true
+
+		for _, action := range rule.Actions {
+			if !validAction(action) {
+				return fmt.Errorf("rule %q: unsupported action %q, expected one of: %v", rule.Name, action, cloud.ChangeActions)
+			}
+		}
+
+		conditions := 0
+		if rule.Deny {
+			conditions++
+		}
+		if rule.MaxChanges != nil {
+			if *rule.MaxChanges < 0 {
+				return fmt.Errorf("rule %q: max_changes must not be negative", rule.Name)
+			}
+			conditions++
+		}
+		if len(rule.RequireLabels) > 0 {
+			conditions++
+		}
+		if conditions != 1 {
+			return fmt.Errorf("rule %q: exactly one of deny, max_changes or require_labels must be set", rule.Name)
+		}
+	}
+	return nil
+}
+
+func validAction(action string) bool {
+	for _, a := range cloud.ChangeActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate returns the violations of changes, labels are matched for require_labels rules
+func (r *Rules) Evaluate(changes []*cloud.ResourceChange, labels []string) []*Violation {
+	hasLabel := map[string]bool{}
+	for _, label := range labels {
+		hasLabel[strings.TrimSpace(label)] = true
+	}
+
+	violations := []*Violation{}
+	for _, rule := range r.Rules {
+		matched := []*cloud.ResourceChange{}
+		for _, change := range changes {
+			if rule.matches(change) {
+				matched = append(matched, change)
+			}
+		}
+
+		switch {
+		case rule.Deny:
+			for _, change := range matched {
+				violations = append(violations, rule.violation(change, fmt.Sprintf("%s of %s is not allowed", change.Action, change.Address)))
+			}
+		case rule.MaxChanges != nil:
+			if len(matched) > *rule.MaxChanges {
+				violations = append(violations, rule.violation(nil, fmt.Sprintf("%d changes exceed the maximum of %d", len(matched), *rule.MaxChanges)))
+			}
+		default:
+			missing := []string{}
+			for _, label := range rule.RequireLabels {
+				if !hasLabel[label] {
+					missing = append(missing, label)
+				}
+			}
+			if len(missing) == 0 {
+				continue
+			}
+			for _, change := range matched {
+				violations = append(violations, rule.violation(change, fmt.Sprintf("%s of %s requires label(s): %s", change.Action, change.Address, strings.Join(missing, ", "))))
+			}
+		}
+	}
+	return violations
+}
+
+func (rule *Rule) violation(change *cloud.ResourceChange, message string) *Violation {
+	v := &Violation{
+		Rule:        rule.Name,
+		Description: rule.Description,
+		Message:     message,
+	}
+	if change != nil {
+		v.Address = change.Address
+		v.Action = change.Action
+	}
+	return v
+}
+
+func (rule *Rule) matches(change *cloud.ResourceChange) bool {
+	if len(rule.Actions) > 0 && !matchAny(rule.Actions, change.Action) {
+		return false
+	}
+	// reading data sources does not change infrastructure
+	if len(rule.Actions) == 0 && change.Action == cloud.ChangeRead {
+		return false
+	}
+	if len(rule.ResourceTypes) > 0 && !matchAny(rule.ResourceTypes, change.Type) {
+		return false
+	}
+	if len(rule.Addresses) > 0 && !matchAny(rule.Addresses, change.Address) {
+		return false
+	}
+	if len(rule.Modules) > 0 {
+		for _, module := range rule.Modules {
+			if matchModule(module, change.Module) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches the module or any of its nested modules and instances
+func matchModule(pattern, module string) bool {
+	if module == "" {
+		return false
+	}
+	if matchPattern(pattern, module) {
+		return true
+	}
+	// compare each parent address, eg. module.a and module.a[0] for module.a[0].module.b
+	for i := range module {
+		if (module[i] == '.' || module[i] == '[') && matchPattern(pattern, module[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches value against pattern, where `*` matches any sequence of characters. Other characters
+// are matched literally, as addresses contain brackets which glob patterns interpret as ranges.
+func matchPattern(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
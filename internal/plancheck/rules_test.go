@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plancheck
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/tfci/internal/cloud"
+)
+
+var testChanges = []*cloud.ResourceChange{
+	{Address: "aws_iam_role.deploy", Type: "aws_iam_role", Action: cloud.ChangeUpdate},
+	{Address: "module.network.aws_subnet.a", Module: "module.network", Type: "aws_subnet", Action: cloud.ChangeReplace},
+	{Address: `module.network["eu"].module.nat.aws_eip.this`, Module: `module.network["eu"].module.nat`, Type: "aws_eip", Action: cloud.ChangeReplace},
+	{Address: "module.networking.aws_vpc.main", Module: "module.networking", Type: "aws_vpc", Action: cloud.ChangeReplace},
+	{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Action: cloud.ChangeDelete},
+	{Address: "aws_s3_bucket.assets", Type: "aws_s3_bucket", Action: cloud.ChangeCreate},
+	{Address: "data.aws_iam_policy_document.deploy", Type: "aws_iam_policy_document", Action: cloud.ChangeRead},
+}
+
+func TestRules_Evaluate(t *testing.T) {
+	rules, err := ParseRules([]byte(`{
+  "rules": [
+    {"name": "keep-buckets", "resource_types": ["aws_s3_bucket"], "actions": ["delete", "replace"], "deny": true},
+    {"name": "small-changes", "max_changes": 5},
+    {"name": "stable-network", "description": "Network changes need a maintenance window", "modules": ["module.network"], "actions": ["replace"], "deny": true},
+    {"name": "iam-review", "resource_types": ["aws_iam_*"], "require_labels": ["iam-approved", "security"]}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []*Violation{
+		{Rule: "keep-buckets", Message: "delete of aws_s3_bucket.logs is not allowed", Address: "aws_s3_bucket.logs", Action: cloud.ChangeDelete},
+		{Rule: "small-changes", Message: "6 changes exceed the maximum of 5"},
+		{Rule: "stable-network", Description: "Network changes need a maintenance window", Message: "replace of module.network.aws_subnet.a is not allowed", Address: "module.network.aws_subnet.a", Action: cloud.ChangeReplace},
+		{Rule: "stable-network", Description: "Network changes need a maintenance window", Message: `replace of module.network["eu"].module.nat.aws_eip.this is not allowed`, Address: `module.network["eu"].module.nat.aws_eip.this`, Action: cloud.ChangeReplace},
+		{Rule: "iam-review", Message: "update of aws_iam_role.deploy requires label(s): security", Address: "aws_iam_role.deploy", Action: cloud.ChangeUpdate},
+	}
+	actual := rules.Evaluate(testChanges, []string{"iam-approved"})
+	if !reflect.DeepEqual(actual, expected) {
+		for _, v := range actual {
+			t.Logf("violation: %+v", v)
+		}
+		t.Errorf("unexpected violations")
+	}
+
+	// labels satisfy the review rule
+	for _, v := range rules.Evaluate(testChanges, []string{"iam-approved", "security"}) {
+		if v.Rule == "iam-review" {
+			t.Errorf("expected labels to satisfy rule, but received: %+v", v)
+		}
+	}
+}
+
+func TestRules_Evaluate_Read(t *testing.T) {
+	rules, err := ParseRules([]byte(`{
+  "rules": [
+    {"name": "no-changes", "max_changes": 0},
+    {"name": "no-reads", "actions": ["read"], "deny": true}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	changes := []*cloud.ResourceChange{testChanges[len(testChanges)-1]}
+	expected := []*Violation{
+		{Rule: "no-reads", Message: "read of data.aws_iam_policy_document.deploy is not allowed", Address: "data.aws_iam_policy_document.deploy", Action: cloud.ChangeRead},
+	}
+	if actual := rules.Evaluate(changes, nil); !reflect.DeepEqual(actual, expected) {
+		for _, v := range actual {
+			t.Logf("violation: %+v", v)
+		}
+		t.Errorf("expected reads to match only rules selecting the read action")
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rules    string
+		expected string
+	}{
+		{name: "empty", rules: `{"rules": []}`, expected: "no rules defined"},
+		{name: "unnamed", rules: `{"rules": [{"deny": true}]}`, expected: "rule 1: name is required"},
+		{name: "duplicate", rules: `{"rules": [{"name": "a", "deny": true}, {"name": "a", "deny": true}]}`, expected: `rule "a": name is not unique`},
+		{name: "action", rules: `{"rules": [{"name": "a", "actions": ["destroy"], "deny": true}]}`, expected: `unsupported action "destroy"`},
+		{name: "no-condition", rules: `{"rules": [{"name": "a", "actions": ["delete"]}]}`, expected: "exactly one of deny, max_changes or require_labels"},
+		{name: "two-conditions", rules: `{"rules": [{"name": "a", "deny": true, "max_changes": 1}]}`, expected: "exactly one of deny, max_changes or require_labels"},
+		{name: "negative-max", rules: `{"rules": [{"name": "a", "max_changes": -1}]}`, expected: "max_changes must not be negative"},
+		{name: "unknown-field", rules: `{"rules": [{"name": "a", "max_change": 1}]}`, expected: `unknown field "max_change"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tc.rules))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected error containing %q, but received: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{pattern: "aws_iam_*", value: "aws_iam_role", expected: true},
+		{pattern: "aws_iam_*", value: "aws_s3_bucket", expected: false},
+		{pattern: "*_policy", value: "aws_iam_policy", expected: true},
+		{pattern: "aws_*_policy", value: "aws_iam_role_policy", expected: true},
+		{pattern: "aws_*_policy", value: "aws_iam_role", expected: false},
+		{pattern: `aws_instance.web[0]`, value: `aws_instance.web[0]`, expected: true},
+		{pattern: `aws_instance.web[0]`, value: `aws_instance.web0`, expected: false},
+		{pattern: "*", value: "anything", expected: true},
+	}
+
+	for _, tc := range testCases {
+		if actual := matchPattern(tc.pattern, tc.value); actual != tc.expected {
+			t.Errorf("expected %t for %q and %q, but received: %t", tc.expected, tc.pattern, tc.value, actual)
+		}
+	}
+}
@@ -5,6 +5,7 @@ package writer
 
 import (
 	"log"
+	"sync"
 
 	"github.com/mitchellh/cli"
 )
@@ -12,6 +13,8 @@ import (
 type Writer struct {
 	json bool
 	ui   cli.Ui
+	// logs are streamed while run status is polled, messages are written one at a time
+	mu sync.Mutex
 }
 
 func NewWriter(ui cli.Ui) *Writer {
@@ -28,6 +31,8 @@ func (w *Writer) UseJson(json bool) {
 // In-Progress diagnostic information
 // if *json is set to true, will send log formatting to stderr
 func (w *Writer) Output(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	if w.json {
 		log.Printf("[INFO] %s", message)
 		return
@@ -39,6 +44,8 @@ func (w *Writer) Output(message string) {
 // Diagnostic error information
 // if *json is set to true, will use log formatting to stderr
 func (w *Writer) Error(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	if w.json {
 		log.Printf("[ERROR] %s", message)
 		return
@@ -51,10 +58,14 @@ func (w *Writer) Error(message string) {
 // regardless of `json` field we will output the message to stdout stream
 // requires the message string is formatted prior to passing to this method receiver
 func (w *Writer) OutputResult(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	w.ui.Output(message)
 }
 
 // Final message sent to stderr stream
 func (w *Writer) ErrorResult(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	w.ui.Error(message)
 }